@@ -0,0 +1,560 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/binadel/jsonw/internal/typefields"
+)
+
+// plan is the way a value of some type is written.
+type plan int
+
+const (
+	planString   plan = iota // StringField
+	planInteger              // IntegerField, for integers that fit an int64
+	planUnsigned             // AnyField of an uint64
+	planFloat                // AnyField of a float64, which is formatted like encoding/json
+	planFloat32              // AnyField of a float32, which is formatted with 32 bits
+	planBoolean              // BooleanField
+	planObject               // ObjectField and the generated WriteJSON method
+	planPointer              // NullField or the plan of the element
+	planSlice                // NullField or ArrayField with the plan of the elements
+	planArray                // ArrayField with the plan of the elements
+	planAny                  // AnyField, for marshalers and everything else
+	planAnyAddr              // AnyField of the address, for marshalers with pointer receivers
+)
+
+// marshalerMethods are the methods making AnyField write a value in its own way.
+var marshalerMethods = []string{"WriteJSONObject", "WriteJSONValue", "MarshalEasyJSON", "MarshalJSON", "MarshalText"}
+
+type generator struct {
+	pkg     *types.Package
+	targets map[*types.TypeName]bool // types getting a WriteJSON method
+	imports map[string]bool
+	buf     bytes.Buffer
+	vars    int // counter naming the variables of the current method
+}
+
+// generate returns the formatted source of the writer methods of the named struct types of pkg.
+func generate(pkg *types.Package, names []string) ([]byte, error) {
+	g := &generator{
+		pkg:     pkg,
+		targets: map[*types.TypeName]bool{},
+		imports: map[string]bool{"github.com/binadel/jsonw/jsoni": true},
+	}
+
+	var objs []*types.TypeName
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(strings.TrimSpace(name)).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", name, pkg.Name())
+		}
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("type %s is not a struct", obj.Name())
+		}
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("type %s is generic", obj.Name())
+		}
+		g.targets[obj] = true
+		objs = append(objs, obj)
+	}
+
+	for _, obj := range objs {
+		if err := g.writeType(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by jsonwgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg.Name())
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		// Standard library imports come first, in their own group.
+		si, sj := isStd(imports[i]), isStd(imports[j])
+		if si != sj {
+			return si
+		}
+		return imports[i] < imports[j]
+	})
+	fmt.Fprintf(&out, "import (\n")
+	for i, path := range imports {
+		if i > 0 && isStd(imports[i-1]) && !isStd(path) {
+			fmt.Fprintf(&out, "\n")
+		}
+		fmt.Fprintf(&out, "%q\n", path)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	return src, nil
+}
+
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) writeType(obj *types.TypeName) error {
+	fields, err := g.fields(obj.Type())
+	if err != nil {
+		return fmt.Errorf("%s: %w", obj.Name(), err)
+	}
+
+	g.vars = 0
+	g.printf("// WriteJSON writes the fields of %s to w.\n", obj.Name())
+	g.printf("func (v %s) WriteJSON(w *jsoni.ObjectWriter) {\n", obj.Name())
+	for _, f := range fields {
+		blocks := 0
+		for _, guard := range f.guards {
+			g.printf("if %s != nil {\n", guard)
+			blocks++
+		}
+		notNil := false
+		if f.omitEmpty {
+			if cond := nonEmpty(f.expr, f.typ); cond != "" {
+				g.printf("if %s {\n", cond)
+				blocks++
+				notNil = true
+			}
+		}
+		if err := g.member("w", f.key, f.expr, f.typ, f.quoted, notNil); err != nil {
+			return fmt.Errorf("%s.%s: %w", obj.Name(), strings.TrimPrefix(f.expr, "v."), err)
+		}
+		g.printf("%s", strings.Repeat("}\n", blocks))
+	}
+	g.printf("}\n\n")
+
+	g.printf("// WriteJSONObject implements jsoni.ObjectMarshaler.\n")
+	g.printf("func (v %s) WriteJSONObject(w *jsoni.ObjectWriter) {\n", obj.Name())
+	g.printf("v.WriteJSON(w)\n")
+	g.printf("}\n\n")
+	return nil
+}
+
+// next returns a fresh variable name with the given prefix.
+func (g *generator) next(prefix string) string {
+	g.vars++
+	return prefix + strconv.Itoa(g.vars)
+}
+
+// plan returns the way values of type t are written.
+func (g *generator) plan(t types.Type) (plan, error) {
+	if named, ok := t.(*types.Named); ok {
+		if g.targets[named.Obj()] {
+			return planObject, nil
+		}
+		// A json.Number is written as the number it holds.
+		if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "encoding/json" && obj.Name() == "Number" {
+			return planAny, nil
+		}
+	}
+	if _, ok := t.Underlying().(*types.Interface); !ok {
+		if hasMarshaler(t, g.pkg, false) {
+			return planAny, nil
+		}
+		if hasMarshaler(t, g.pkg, true) {
+			return planAnyAddr, nil
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.String:
+			return planString, nil
+		case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+			types.Uint8, types.Uint16, types.Uint32:
+			return planInteger, nil
+		case types.Uint, types.Uint64, types.Uintptr:
+			return planUnsigned, nil
+		case types.Float64:
+			return planFloat, nil
+		case types.Float32:
+			return planFloat32, nil
+		case types.Bool:
+			return planBoolean, nil
+		}
+	case *types.Pointer:
+		return planPointer, nil
+	case *types.Slice:
+		// Byte slices are written as base64 strings by AnyField.
+		if b, ok := u.Elem().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return planAny, nil
+		}
+		return planSlice, nil
+	case *types.Array:
+		return planArray, nil
+	case *types.Map, *types.Interface, *types.Struct:
+		return planAny, nil
+	}
+	return 0, fmt.Errorf("unsupported type %s", types.TypeString(t, types.RelativeTo(g.pkg)))
+}
+
+// hasMarshaler reports whether values of type t, or their addresses if addr is set,
+// write themselves when passed to AnyField.
+func hasMarshaler(t types.Type, pkg *types.Package, addr bool) bool {
+	if addr {
+		t = types.NewPointer(t)
+	}
+	for _, name := range marshalerMethods {
+		if obj, _, _ := types.LookupFieldOrMethod(t, false, pkg, name); obj != nil {
+			if _, ok := obj.(*types.Func); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOwnMarshaler reports whether values of type t are written by their own MarshalJSON
+// or MarshalText method, which the generated methods would take precedence over in AnyField.
+func hasOwnMarshaler(t types.Type, pkg *types.Package) bool {
+	for _, name := range []string{"MarshalJSON", "MarshalText"} {
+		if obj, _, _ := types.LookupFieldOrMethod(t, true, pkg, name); obj != nil {
+			if _, ok := obj.(*types.Func); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// member writes the value expr of type t as the member key of the object writer w.
+// The nil check of pointers and slices is left out if notNil is set.
+func (g *generator) member(w, key, expr string, t types.Type, quoted, notNil bool) error {
+	p, err := g.plan(t)
+	if err != nil {
+		return err
+	}
+
+	if quoted {
+		switch p {
+		case planInteger:
+			g.imports["strconv"] = true
+			g.printf("%s.StringField(%s, strconv.FormatInt(%s, 10))\n", w, key, convert(expr, t, types.Typ[types.Int64]))
+			return nil
+		case planUnsigned:
+			g.imports["strconv"] = true
+			g.printf("%s.StringField(%s, strconv.FormatUint(%s, 10))\n", w, key, convert(expr, t, types.Typ[types.Uint64]))
+			return nil
+		case planBoolean:
+			g.imports["strconv"] = true
+			g.printf("%s.StringField(%s, strconv.FormatBool(%s))\n", w, key, convert(expr, t, types.Typ[types.Bool]))
+			return nil
+		case planString, planFloat, planFloat32:
+			return fmt.Errorf("the string option is only supported on integer and boolean fields")
+		}
+	}
+
+	switch p {
+	case planString:
+		g.printf("%s.StringField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.String]))
+	case planInteger:
+		g.printf("%s.IntegerField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.Int64]))
+	case planUnsigned:
+		g.printf("%s.AnyField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.Uint64]))
+	case planFloat:
+		g.printf("%s.AnyField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.Float64]))
+	case planFloat32:
+		g.printf("%s.AnyField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.Float32]))
+	case planBoolean:
+		g.printf("%s.BooleanField(%s, %s)\n", w, key, convert(expr, t, types.Typ[types.Bool]))
+	case planAny:
+		g.printf("%s.AnyField(%s, %s)\n", w, key, expr)
+	case planAnyAddr:
+		g.printf("%s.AnyField(%s, &%s)\n", w, key, expr)
+	case planObject:
+		obj := g.next("obj")
+		g.printf("%s := %s.ObjectField(%s)\n", obj, w, key)
+		g.printf("%s.Open()\n", obj)
+		g.printf("%s.WriteJSON(&%s)\n", expr, obj)
+		g.printf("%s.Close()\n", obj)
+	case planPointer:
+		elem := t.Underlying().(*types.Pointer).Elem()
+		ep, err := g.plan(elem)
+		if err != nil {
+			return err
+		}
+		if ep == planAny {
+			// AnyField writes nil pointers as null itself.
+			g.printf("%s.AnyField(%s, %s)\n", w, key, expr)
+			return nil
+		}
+		if notNil {
+			return g.member(w, key, deref(expr, ep), elem, quoted, false)
+		}
+		g.printf("if %s == nil {\n", expr)
+		g.printf("%s.NullField(%s)\n", w, key)
+		g.printf("} else {\n")
+		if err := g.member(w, key, deref(expr, ep), elem, quoted, false); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case planSlice, planArray:
+		elem := elemType(t)
+		ep, err := g.plan(elem)
+		if err != nil {
+			return err
+		}
+		if ep == planAny {
+			g.printf("%s.AnyField(%s, %s)\n", w, key, expr)
+			return nil
+		}
+		nilCheck := p == planSlice && !notNil
+		if nilCheck {
+			g.printf("if %s == nil {\n", expr)
+			g.printf("%s.NullField(%s)\n", w, key)
+			g.printf("} else {\n")
+		}
+		arr := g.next("arr")
+		g.printf("%s := %s.ArrayField(%s)\n", arr, w, key)
+		if err := g.elements(arr, expr, elem); err != nil {
+			return err
+		}
+		if nilCheck {
+			g.printf("}\n")
+		}
+	}
+	return nil
+}
+
+// value writes expr of type t as the next value of the array writer a.
+func (g *generator) value(a, expr string, t types.Type) error {
+	p, err := g.plan(t)
+	if err != nil {
+		return err
+	}
+
+	switch p {
+	case planString:
+		g.printf("%s.StringValue(%s)\n", a, convert(expr, t, types.Typ[types.String]))
+	case planInteger:
+		g.printf("%s.IntegerValue(%s)\n", a, convert(expr, t, types.Typ[types.Int64]))
+	case planUnsigned:
+		g.printf("%s.AnyValue(%s)\n", a, convert(expr, t, types.Typ[types.Uint64]))
+	case planFloat:
+		g.printf("%s.AnyValue(%s)\n", a, convert(expr, t, types.Typ[types.Float64]))
+	case planFloat32:
+		g.printf("%s.AnyValue(%s)\n", a, convert(expr, t, types.Typ[types.Float32]))
+	case planBoolean:
+		g.printf("%s.BooleanValue(%s)\n", a, convert(expr, t, types.Typ[types.Bool]))
+	case planAny:
+		g.printf("%s.AnyValue(%s)\n", a, expr)
+	case planAnyAddr:
+		g.printf("%s.AnyValue(&%s)\n", a, expr)
+	case planObject:
+		obj := g.next("obj")
+		g.printf("%s := %s.ObjectValue()\n", obj, a)
+		g.printf("%s.Open()\n", obj)
+		g.printf("%s.WriteJSON(&%s)\n", expr, obj)
+		g.printf("%s.Close()\n", obj)
+	case planPointer:
+		elem := t.Underlying().(*types.Pointer).Elem()
+		ep, err := g.plan(elem)
+		if err != nil {
+			return err
+		}
+		if ep == planAny {
+			g.printf("%s.AnyValue(%s)\n", a, expr)
+			return nil
+		}
+		g.printf("if %s == nil {\n", expr)
+		g.printf("%s.NullValue()\n", a)
+		g.printf("} else {\n")
+		if err := g.value(a, deref(expr, ep), elem); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case planSlice, planArray:
+		elem := elemType(t)
+		ep, err := g.plan(elem)
+		if err != nil {
+			return err
+		}
+		if ep == planAny {
+			g.printf("%s.AnyValue(%s)\n", a, expr)
+			return nil
+		}
+		if p == planSlice {
+			g.printf("if %s == nil {\n", expr)
+			g.printf("%s.NullValue()\n", a)
+			g.printf("} else {\n")
+		}
+		arr := g.next("arr")
+		g.printf("%s := %s.ArrayValue()\n", arr, a)
+		if err := g.elements(arr, expr, elem); err != nil {
+			return err
+		}
+		if p == planSlice {
+			g.printf("}\n")
+		}
+	}
+	return nil
+}
+
+// elements writes the elements of the slice or array expr to the array writer arr, opening and closing it.
+func (g *generator) elements(arr, expr string, elem types.Type) error {
+	item := g.next("item")
+	g.printf("%s.Open()\n", arr)
+	g.printf("for _, %s := range %s {\n", item, expr)
+	if err := g.value(arr, item, elem); err != nil {
+		return err
+	}
+	g.printf("}\n")
+	g.printf("%s.Close()\n", arr)
+	return nil
+}
+
+func elemType(t types.Type) types.Type {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return u.Elem()
+	case *types.Array:
+		return u.Elem()
+	}
+	panic("not a slice or array: " + t.String())
+}
+
+// deref returns the expression of the value pointed to by expr.
+// Generated methods are called through the pointer, which Go dereferences itself.
+func deref(expr string, p plan) string {
+	if p == planObject {
+		return expr
+	}
+	return "*" + expr
+}
+
+// convert returns expr converted to the basic type to, unless it already has that type.
+func convert(expr string, t, to types.Type) string {
+	if types.Identical(t, to) {
+		return expr
+	}
+	return to.String() + "(" + expr + ")"
+}
+
+// nonEmpty returns the condition under which a field with the omitempty option is written,
+// or an empty string if it is always written, like encoding/json does for structs.
+func nonEmpty(expr string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return expr
+		case u.Info()&types.IsString != 0:
+			return expr + ` != ""`
+		default:
+			return expr + " != 0"
+		}
+	case *types.Pointer, *types.Interface:
+		return expr + " != nil"
+	case *types.Slice, *types.Map:
+		return "len(" + expr + ") != 0"
+	case *types.Array:
+		if u.Len() == 0 {
+			return "false"
+		}
+	}
+	return ""
+}
+
+// field is a member written by a generated method.
+type field struct {
+	key       string   // the JSON key as an escaped Go string literal
+	expr      string   // the selector of the field from the receiver v
+	guards    []string // embedded pointers that must not be nil
+	typ       types.Type
+	omitEmpty bool
+	quoted    bool
+}
+
+// fields returns the fields of the struct type t that encoding/json writes, resolving the
+// names promoted from embedded structs with the same rules.
+func (g *generator) fields(t types.Type) ([]field, error) {
+	var fields []field
+	for _, f := range typefields.Resolve(t, structMembers) {
+		// The selector is rebuilt from the index sequence, guarding embedded pointers.
+		expr := "v"
+		var guards []string
+		s := t.Underlying().(*types.Struct)
+		for k, i := range f.Index {
+			sf := s.Field(i)
+			if !sf.Exported() && sf.Pkg() != g.pkg {
+				return nil, fmt.Errorf("cannot access unexported embedded field %s of package %s", sf.Name(), sf.Pkg().Path())
+			}
+			expr += "." + sf.Name()
+			if k == len(f.Index)-1 {
+				break
+			}
+			et := sf.Type()
+			if p, ok := et.(*types.Pointer); ok {
+				guards = append(guards, expr)
+				et = p.Elem()
+			}
+			s = et.Underlying().(*types.Struct)
+		}
+
+		fields = append(fields, field{
+			key:       quoteKey(f.Name),
+			expr:      expr,
+			guards:    guards,
+			typ:       f.Type,
+			omitEmpty: f.OmitEmpty,
+			quoted:    f.Quoted,
+		})
+	}
+	return fields, nil
+}
+
+// structMembers lists the fields of a struct type for typefields.Resolve.
+func structMembers(t types.Type) []typefields.Member[types.Type] {
+	s := t.Underlying().(*types.Struct)
+	members := make([]typefields.Member[types.Type], s.NumFields())
+	for i := range members {
+		sf := s.Field(i)
+		elem := sf.Type()
+		if p, ok := elem.(*types.Pointer); ok {
+			elem = p.Elem()
+		}
+		_, isStruct := elem.Underlying().(*types.Struct)
+		b, isBasic := elem.Underlying().(*types.Basic)
+
+		members[i] = typefields.Member[types.Type]{
+			Name:     sf.Name(),
+			Tag:      reflect.StructTag(s.Tag(i)).Get("json"),
+			Exported: sf.Exported(),
+			Embedded: sf.Embedded(),
+			Type:     sf.Type(),
+			Elem:     elem,
+			Struct:   isStruct,
+			Scalar:   isBasic && b.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0,
+		}
+	}
+	return members
+}
+
+// quoteKey returns the key escaped like encoding/json escapes it, as a Go string literal
+// to be passed to the field methods of jsoni, which write names as they are.
+func quoteKey(name string) string {
+	escaped, _ := json.Marshal(name)
+	return strconv.Quote(string(escaped[1 : len(escaped)-1]))
+}
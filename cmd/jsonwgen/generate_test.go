@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// fset and imports are shared by the tests, so that dependencies are type-checked once.
+var (
+	fset    = token.NewFileSet()
+	imports = importer.ForCompiler(fset, "source", nil)
+)
+
+func checkFiles(t *testing.T, path string, files ...*ast.File) (*types.Package, error) {
+	t.Helper()
+	conf := types.Config{Importer: imports}
+	return conf.Check(path, fset, files, nil)
+}
+
+// generateFile generates the writer methods of every struct in a standalone source file.
+func generateFile(t *testing.T, name string, names ...string) ([]byte, error) {
+	t.Helper()
+
+	file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	pkg, err := checkFiles(t, file.Name.Name, file)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if len(names) == 0 {
+		names = fileStructs(file, pkg)
+	}
+	return generate(pkg, names)
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".go")
+		t.Run(name, func(t *testing.T) {
+			result, err := generateFile(t, input)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			// The output must compile along with its input.
+			var files []*ast.File
+			for _, src := range []any{nil, result} {
+				name := input
+				if src != nil {
+					name = "output.go"
+				}
+				file, err := parser.ParseFile(fset, name, src, 0)
+				if err != nil {
+					t.Fatalf("ParseFile failed: %v", err)
+				}
+				files = append(files, file)
+			}
+			if _, err := checkFiles(t, name, files...); err != nil {
+				t.Errorf("Output does not compile: %v", err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, result, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != string(expected) {
+				t.Errorf("Output differs from %s:\n%s", golden, result)
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		types []string
+		err   string
+	}{
+		{
+			name:  "unknown type",
+			src:   "package p\n",
+			types: []string{"Missing"},
+			err:   "type Missing not found",
+		},
+		{
+			name:  "not a struct",
+			src:   "package p\ntype Level int\n",
+			types: []string{"Level"},
+			err:   "type Level is not a struct",
+		},
+		{
+			name: "unsupported field type",
+			src:  "package p\ntype T struct{ C chan int }\n",
+			err:  "T.C: unsupported type chan int",
+		},
+		{
+			name: "string option on a float",
+			src:  "package p\ntype T struct{ F float64 `json:\",string\"` }\n",
+			err:  "T.F: the string option is only supported on integer and boolean fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "p.go")
+			if err := os.WriteFile(name, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := generateFile(t, name, tt.types...)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
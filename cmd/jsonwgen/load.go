@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// loadedPackage is a parsed and type-checked package.
+type loadedPackage struct {
+	fset  *token.FileSet
+	files map[string]*ast.File // by absolute file name
+	types *types.Package
+}
+
+// load parses and type-checks the package in dir, leaving out the previous output,
+// which may be stale or may not compile.
+func load(dir, output string) (*loadedPackage, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := filepath.Abs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := map[string]*ast.File{}
+	var list []*ast.File
+	for _, name := range bp.GoFiles {
+		path, err := filepath.Abs(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if path == exclude {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = file
+		list = append(list, file)
+	}
+
+	pkg, err := check(fset, bp.ImportPath, list)
+	if err != nil {
+		return nil, err
+	}
+	return &loadedPackage{fset: fset, files: files, types: pkg}, nil
+}
+
+// check type-checks files, importing dependencies from source.
+func check(fset *token.FileSet, path string, files []*ast.File) (*types.Package, error) {
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(path, fset, files, nil)
+}
+
+// structs returns the names of the struct types to generate by default for the given files.
+func (p *loadedPackage) structs(names []string) ([]string, error) {
+	var result []string
+	for _, name := range names {
+		path, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		file, ok := p.files[path]
+		if !ok {
+			return nil, fmt.Errorf("%s is not part of package %s", name, p.types.Name())
+		}
+		result = append(result, fileStructs(file, p.types)...)
+	}
+	return result, nil
+}
+
+// fileStructs returns the names of the struct types declared in file, leaving out generic types
+// and those with their own MarshalJSON or MarshalText method.
+func fileStructs(file *ast.File, pkg *types.Package) []string {
+	var result []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.StructType); !ok || ts.TypeParams != nil {
+				continue
+			}
+			if obj := pkg.Scope().Lookup(ts.Name.Name); obj != nil && !hasOwnMarshaler(obj.Type(), pkg) {
+				result = append(result, ts.Name.Name)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+// Command jsonwgen generates jsoni writer methods for Go struct types.
+//
+// For every selected struct type T it emits
+//
+//	func (v T) WriteJSON(w *jsoni.ObjectWriter)
+//
+// which writes the fields of T, named and filtered by their json tags like encoding/json
+// does, and a WriteJSONObject method making T a jsoni.ObjectMarshaler.
+//
+// Usage:
+//
+//	jsonwgen [-type T1,T2] [-output file] [file.go ...]
+//
+// Without files, the file named by $GOFILE is used, so it can be run from a
+// go:generate directive:
+//
+//	//go:generate go run github.com/binadel/jsonw/cmd/jsonwgen -type User,Post
+//
+// Without -type, every struct type declared in the given files is generated, except for
+// those with their own MarshalJSON or MarshalText method.
+// The output defaults to the first file with a _jsonw.go suffix.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct types; all structs of the files if empty")
+	output := flag.String("output", "", "output file name; default <file>_jsonw.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: jsonwgen [-type T1,T2] [-output file] [file.go ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*typeNames, *output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "jsonwgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(typeNames, output string, files []string) error {
+	if len(files) == 0 {
+		gofile := os.Getenv("GOFILE")
+		if gofile == "" {
+			return fmt.Errorf("no input files")
+		}
+		files = []string{gofile}
+	}
+
+	dir := filepath.Dir(files[0])
+	for _, file := range files[1:] {
+		if filepath.Dir(file) != dir {
+			return fmt.Errorf("files must be in a single directory")
+		}
+	}
+
+	if output == "" {
+		output = strings.TrimSuffix(files[0], ".go") + "_jsonw.go"
+	}
+
+	pkg, err := load(dir, output)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if typeNames != "" {
+		names = strings.Split(typeNames, ",")
+	} else {
+		names, err = pkg.structs(files)
+		if err != nil {
+			return err
+		}
+	}
+
+	src, err := generate(pkg.types, names)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
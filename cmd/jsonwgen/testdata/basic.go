@@ -0,0 +1,32 @@
+package basic
+
+type Level uint8
+
+type Name string
+
+type Basic struct {
+	Text     string  `json:"text"`
+	Int      int     `json:"int"`
+	Int64    int64   `json:"int64"`
+	Uint     uint    `json:"uint"`
+	Small    uint16  `json:"small"`
+	Float    float64 `json:"float"`
+	Float32  float32 `json:"float32"`
+	Flag     bool    `json:"flag"`
+	Level    Level   `json:"level"`
+	Name     Name    `json:"name"`
+	Untagged string
+	Escaped  string `json:"<escaped>"`
+	Skipped  string `json:"-"`
+	Dash     string `json:"-,"`
+	private  string
+
+	OptText  string `json:"opt_text,omitempty"`
+	OptInt   int    `json:"opt_int,omitempty"`
+	OptFlag  bool   `json:"opt_flag,omitempty"`
+	OptLevel Level  `json:",omitempty"`
+
+	Count   int64 `json:"count,string"`
+	Big     uint  `json:"big,string"`
+	Enabled bool  `json:"enabled,string"`
+}
@@ -0,0 +1,25 @@
+package embedded
+
+type Base struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Note string
+}
+
+type Extra struct {
+	Note  string
+	Level int `json:"level,omitempty"`
+}
+
+type inner struct {
+	Secret string `json:"secret"`
+}
+
+type Embedded struct {
+	Base
+	*Extra
+	inner
+
+	Name  string `json:"display"`
+	Named Base   `json:"named"`
+}
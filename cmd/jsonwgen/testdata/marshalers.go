@@ -0,0 +1,33 @@
+package marshalers
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+)
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte([...]string{"off", "on"}[s]), nil
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (m *Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(m.Cents) / 100)
+}
+
+type Marshalers struct {
+	Status   Status          `json:"status"`
+	Price    Money           `json:"price"`
+	Prices   []Money         `json:"prices"`
+	Stamp    time.Time       `json:"stamp"`
+	Deadline *time.Time      `json:"deadline,omitempty"`
+	Timeout  time.Duration   `json:"timeout"`
+	Number   json.Number     `json:"number,omitempty"`
+	Raw      json.RawMessage `json:"raw"`
+	IP       net.IP          `json:"ip"`
+}
@@ -0,0 +1,27 @@
+package nested
+
+import "time"
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Nested struct {
+	Address   Address            `json:"address"`
+	Owner     *Address           `json:"owner"`
+	Optional  *Address           `json:"optional,omitempty"`
+	Count     *int               `json:"count"`
+	Quoted    *int               `json:"quoted,string"`
+	Tags      []string           `json:"tags"`
+	OptTags   []string           `json:"opt_tags,omitempty"`
+	Addresses []Address          `json:"addresses"`
+	Pointers  []*Address         `json:"pointers"`
+	Matrix    [][]float64        `json:"matrix"`
+	Fixed     [2]int             `json:"fixed"`
+	Data      []byte             `json:"data"`
+	Meta      map[string]string  `json:"meta"`
+	Index     map[string]Address `json:"index,omitempty"`
+	Any       any                `json:"any"`
+	Anon      struct{ X int }    `json:"anon"`
+	Times     []time.Time        `json:"times"`
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// options control the generated code.
+type options struct {
+	style        string // imperative or declarative
+	pkg          string // declarative package
+	packageName  string
+	funcName     string
+	placeholders bool
+	source       string // name of the sample file, for the comments
+}
+
+// placeholder marks the sample values to be replaced with real data.
+const placeholder = " // TODO: parameterize"
+
+type emitter struct {
+	options
+	buf   bytes.Buffer
+	names map[string]int // variable names used so far, for the imperative style
+}
+
+// emit returns the formatted source of a function writing the document root.
+func emit(root *node, opts options) ([]byte, error) {
+	if root.kind != kindObject && root.kind != kindArray {
+		return nil, errors.New("the top-level value must be an object or an array")
+	}
+
+	e := &emitter{options: opts, names: map[string]int{}}
+	e.printf("// Generated by jsonwsample from %s.\n", filepath.Base(opts.source))
+	if opts.placeholders {
+		e.printf("// Replace the values marked TODO with real data.\n")
+	}
+	e.printf("\npackage %s\n\n", opts.packageName)
+
+	switch opts.style {
+	case "imperative":
+		e.imperative(root)
+	case "declarative":
+		switch opts.pkg {
+		case "jsondf", "jsondi", "jsonds":
+		default:
+			return nil, fmt.Errorf("unknown declarative package %q", opts.pkg)
+		}
+		e.declarative(root)
+	default:
+		return nil, fmt.Errorf("unknown style %q", opts.style)
+	}
+
+	src, err := format.Source(e.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	return src, nil
+}
+
+func (e *emitter) printf(format string, args ...any) {
+	fmt.Fprintf(&e.buf, format, args...)
+}
+
+// mark ends the line of a sample value, with a placeholder comment if enabled.
+func (e *emitter) mark() {
+	if e.placeholders {
+		e.printf(placeholder)
+	}
+	e.printf("\n")
+}
+
+func (e *emitter) header() {
+	e.printf("// %s writes a document shaped like %s.\n", e.funcName, filepath.Base(e.source))
+	e.printf("func %s() ([]byte, error) {\n", e.funcName)
+}
+
+func (e *emitter) imperative(root *node) {
+	e.printf("import \"github.com/binadel/jsonw/jsoni\"\n\n")
+	e.header()
+	e.names["w"] = 1
+	if root.kind == kindObject {
+		e.printf("w := jsoni.NewObjectWriter(nil)\n")
+		e.printf("w.Open()\n")
+		e.members("w", root)
+	} else {
+		e.printf("w := jsoni.NewArrayWriter(nil)\n")
+		e.printf("w.Open()\n")
+		e.elements("w", root)
+	}
+	e.printf("w.Close()\n")
+	e.printf("return w.BuildBytes()\n")
+	e.printf("}\n")
+}
+
+// members writes the members of the object n to the object writer w.
+func (e *emitter) members(w string, n *node) {
+	for _, c := range n.children {
+		key := quoteKey(c.key)
+		switch c.kind {
+		case kindObject:
+			obj := e.name(c.key, "obj")
+			e.printf("%s := %s.ObjectField(%s)\n", obj, w, key)
+			e.printf("%s.Open()\n", obj)
+			e.members(obj, c)
+			e.printf("%s.Close()\n", obj)
+		case kindArray:
+			arr := e.name(c.key, "arr")
+			e.printf("%s := %s.ArrayField(%s)\n", arr, w, key)
+			e.printf("%s.Open()\n", arr)
+			e.elements(arr, c)
+			e.printf("%s.Close()\n", arr)
+		case kindString:
+			e.printf("%s.StringField(%s, %s)", w, key, strconv.Quote(c.value))
+			e.mark()
+		case kindNumber:
+			method, literal := number(c.value)
+			e.printf("%s.%sField(%s, %s)", w, method, key, literal)
+			e.mark()
+		case kindBoolean:
+			e.printf("%s.BooleanField(%s, %s)", w, key, c.value)
+			e.mark()
+		case kindNull:
+			e.printf("%s.NullField(%s)\n", w, key)
+		}
+	}
+}
+
+// elements writes the elements of the array n to the array writer a.
+func (e *emitter) elements(a string, n *node) {
+	for _, c := range n.children {
+		switch c.kind {
+		case kindObject:
+			obj := e.name("", "obj")
+			e.printf("%s := %s.ObjectValue()\n", obj, a)
+			e.printf("%s.Open()\n", obj)
+			e.members(obj, c)
+			e.printf("%s.Close()\n", obj)
+		case kindArray:
+			arr := e.name("", "arr")
+			e.printf("%s := %s.ArrayValue()\n", arr, a)
+			e.printf("%s.Open()\n", arr)
+			e.elements(arr, c)
+			e.printf("%s.Close()\n", arr)
+		case kindString:
+			e.printf("%s.StringValue(%s)", a, strconv.Quote(c.value))
+			e.mark()
+		case kindNumber:
+			method, literal := number(c.value)
+			e.printf("%s.%sValue(%s)", a, method, literal)
+			e.mark()
+		case kindBoolean:
+			e.printf("%s.BooleanValue(%s)", a, c.value)
+			e.mark()
+		case kindNull:
+			e.printf("%s.NullValue()\n", a)
+		}
+	}
+}
+
+// name returns an unused variable name for the writer of the member key,
+// falling back to a generic name for keys that don't make a good identifier.
+func (e *emitter) name(key, fallback string) string {
+	name := identifier(key)
+	if name == "" {
+		name = fallback
+	} else if token.IsKeyword(name) || name == "jsoni" {
+		name += strings.ToUpper(fallback[:1]) + fallback[1:]
+	}
+
+	e.names[name]++
+	if n := e.names[name]; n > 1 {
+		return name + strconv.Itoa(n)
+	}
+	return name
+}
+
+// identifier converts a key like "user_id" or "avatar-url" to lower camel case,
+// returning an empty string if nothing usable is left.
+func identifier(key string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if b.Len() == 0 {
+				if unicode.IsDigit(r) {
+					continue
+				}
+				r = unicode.ToLower(r)
+			} else if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
+
+func (e *emitter) declarative(root *node) {
+	e.printf("import json %q\n\n", "github.com/binadel/jsonw/"+e.pkg)
+	e.header()
+	if root.kind == kindObject {
+		e.printf("return json.New(\n")
+		e.fields(root)
+	} else {
+		e.printf("return json.NewArray(\n")
+		e.values(root)
+	}
+	e.printf(").Build()\n")
+	e.printf("}\n")
+}
+
+// fields writes the members of the object n as declarative fields, one per line.
+func (e *emitter) fields(n *node) {
+	for _, c := range n.children {
+		key := quoteKey(c.key)
+		switch c.kind {
+		case kindObject:
+			e.printf("json.Object(%s", key)
+			if len(c.children) > 0 {
+				e.printf(",\n")
+				e.fields(c)
+			}
+			e.printf("),\n")
+		case kindArray:
+			e.printf("json.Array(%s", key)
+			if len(c.children) > 0 {
+				e.printf(",\n")
+				e.values(c)
+			}
+			e.printf("),\n")
+		case kindString:
+			e.printf("json.String(%s, %s),", key, strconv.Quote(c.value))
+			e.mark()
+		case kindNumber:
+			method, literal := number(c.value)
+			e.printf("json.%s(%s, %s),", method, key, literal)
+			e.mark()
+		case kindBoolean:
+			e.printf("json.Boolean(%s, %s),", key, c.value)
+			e.mark()
+		case kindNull:
+			e.printf("json.Null(%s),\n", key)
+		}
+	}
+}
+
+// values writes the elements of the array n as declarative values, one per line.
+func (e *emitter) values(n *node) {
+	for _, c := range n.children {
+		switch c.kind {
+		case kindObject:
+			e.printf("json.ObjectItem(")
+			if len(c.children) > 0 {
+				e.printf("\n")
+				e.fields(c)
+			}
+			e.printf("),\n")
+		case kindArray:
+			e.printf("json.ArrayItem(")
+			if len(c.children) > 0 {
+				e.printf("\n")
+				e.values(c)
+			}
+			e.printf("),\n")
+		case kindString:
+			e.printf("json.StringItem(%s),", strconv.Quote(c.value))
+			e.mark()
+		case kindNumber:
+			method, literal := number(c.value)
+			e.printf("json.%sItem(%s),", method, literal)
+			e.mark()
+		case kindBoolean:
+			e.printf("json.BooleanItem(%s),", c.value)
+			e.mark()
+		case kindNull:
+			e.printf("json.NullItem(),\n")
+		}
+	}
+}
+
+// number chooses how a number literal is written, by its form: integers that fit
+// an int64 as Integer, other numbers as Float if that writes the same literal back,
+// and the rest as Number, which keeps the literal as it is.
+// It returns the method name and its Go argument.
+func number(literal string) (string, string) {
+	if !strings.ContainsAny(literal, ".eE") {
+		if v, err := strconv.ParseInt(literal, 10, 64); err == nil && strconv.FormatInt(v, 10) == literal {
+			return "Integer", literal
+		}
+	} else if v, err := strconv.ParseFloat(literal, 64); err == nil && strconv.FormatFloat(v, 'g', -1, 64) == literal {
+		return "Float", literal
+	}
+	return "Number", strconv.Quote(literal)
+}
+
+// quoteKey returns the key escaped like encoding/json escapes it, as a Go string literal
+// to be passed to the field methods, which write names as they are.
+func quoteKey(name string) string {
+	escaped, _ := json.Marshal(name)
+	return strconv.Quote(string(escaped[1 : len(escaped)-1]))
+}
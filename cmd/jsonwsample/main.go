@@ -0,0 +1,68 @@
+// Command jsonwsample turns a sample JSON document into Go code writing the same document,
+// either with the jsoni writers or with one of the declarative packages.
+//
+// Usage:
+//
+//	jsonwsample [-style imperative|declarative] [-pkg jsondf|jsondi|jsonds]
+//		[-package name] [-func name] [-output file] [-placeholders=false] sample.json
+//
+// Members keep the order of the sample. Integers that fit an int64 are written with
+// Integer, other numbers with Float when that reproduces the literal exactly and with
+// Number otherwise. Every string, number and boolean is marked with a TODO comment,
+// as a placeholder to be replaced with real data.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.style, "style", "imperative", "code style: imperative or declarative")
+	flag.StringVar(&opts.pkg, "pkg", "jsondf", "declarative package: jsondf, jsondi or jsonds")
+	flag.StringVar(&opts.packageName, "package", "main", "package name of the output")
+	flag.StringVar(&opts.funcName, "func", "writeSample", "name of the generated function")
+	flag.BoolVar(&opts.placeholders, "placeholders", true, "mark sample values with TODO comments")
+	output := flag.String("output", "", "output file; standard output if empty")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: jsonwsample [flags] sample.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(opts, flag.Arg(0), *output); err != nil {
+		fmt.Fprintf(os.Stderr, "jsonwsample: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options, input, output string) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+
+	root, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	opts.source = input
+	src, err := emit(root, opts)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestEmit_Golden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+
+	for _, input := range inputs {
+		for _, opts := range []options{
+			{style: "imperative"},
+			{style: "declarative", pkg: "jsondf"},
+		} {
+			name := strings.TrimSuffix(filepath.Base(input), ".json") + "." + opts.style
+			t.Run(name, func(t *testing.T) {
+				data, err := os.ReadFile(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				root, err := parse(data)
+				if err != nil {
+					t.Fatalf("parse failed: %v", err)
+				}
+
+				opts.packageName, opts.funcName, opts.placeholders, opts.source = "sample", "writeSample", true, input
+				result, err := emit(root, opts)
+				if err != nil {
+					t.Fatalf("emit failed: %v", err)
+				}
+
+				file, err := parser.ParseFile(fset, name+".go", result, 0)
+				if err != nil {
+					t.Fatalf("ParseFile failed: %v", err)
+				}
+				if _, err := conf.Check("sample", fset, []*ast.File{file}, nil); err != nil {
+					t.Errorf("Output does not compile: %v", err)
+				}
+
+				golden := filepath.Join("testdata", name+".golden")
+				if *update {
+					if err := os.WriteFile(golden, result, 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				expected, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(result) != string(expected) {
+					t.Errorf("Output differs from %s:\n%s", golden, result)
+				}
+			})
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		literal string
+		method  string
+		arg     string
+	}{
+		{"0", "Integer", "0"},
+		{"-273", "Integer", "-273"},
+		{"-0", "Number", `"-0"`},
+		{"23565849841318736104", "Number", `"23565849841318736104"`},
+		{"73.2", "Float", "73.2"},
+		{"0.0", "Number", `"0.0"`},
+		{"1e3", "Number", `"1e3"`},
+		{"1e+21", "Float", "1e+21"},
+	}
+
+	for _, tt := range tests {
+		if method, arg := number(tt.literal); method != tt.method || arg != tt.arg {
+			t.Errorf("number(%q) = %s, %s; expected %s, %s", tt.literal, method, arg, tt.method, tt.arg)
+		}
+	}
+}
+
+func TestEmit_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		opts options
+		err  string
+	}{
+		{"scalar root", `"text"`, options{style: "imperative"}, "the top-level value must be an object or an array"},
+		{"unknown style", `{}`, options{style: "fluent"}, `unknown style "fluent"`},
+		{"unknown package", `{}`, options{style: "declarative", pkg: "jsondx"}, `unknown declarative package "jsondx"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parse([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			tt.opts.packageName, tt.opts.funcName = "sample", "writeSample"
+			if _, err := emit(root, tt.opts); err == nil || err.Error() != tt.err {
+				t.Errorf("Expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := parse([]byte(`{} {}`)); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+}
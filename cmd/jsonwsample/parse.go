@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// kind is the type of a JSON value.
+type kind int
+
+const (
+	kindObject kind = iota
+	kindArray
+	kindString
+	kindNumber
+	kindBoolean
+	kindNull
+)
+
+// node is a value of the sample, with its members or elements in document order.
+type node struct {
+	kind     kind
+	key      string // member name, if the node is an object member
+	value    string // string contents, number literal, or "true"/"false"
+	children []*node
+}
+
+// parse reads a single JSON document, keeping member order and number literals.
+func parse(data []byte) (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := parseValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return root, nil
+}
+
+func parseValue(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			n := &node{kind: kindObject}
+			for dec.More() {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				child.key = tok.(string)
+				n.children = append(n.children, child)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+
+		n := &node{kind: kindArray}
+		for dec.More() {
+			child, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+		_, err := dec.Token()
+		return n, err
+	case string:
+		return &node{kind: kindString, value: v}, nil
+	case json.Number:
+		return &node{kind: kindNumber, value: v.String()}, nil
+	case bool:
+		return &node{kind: kindBoolean, value: fmt.Sprint(v)}, nil
+	case nil:
+		return &node{kind: kindNull}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
@@ -0,0 +1,85 @@
+// Package frozen caches the encoding of the immutable subtrees of the declarative packages.
+package frozen
+
+import (
+	"sync"
+
+	"github.com/binadel/jsonw/internal/hooks"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Cache is the encoding of an immutable subtree, made once by its first write. Since the output
+// depends on the configuration of the writer, such as its NoEscapeHTML setting, an encoding is
+// made for each configuration the subtree is written with. It is safe for concurrent use.
+type Cache struct {
+	write     func(w *jwriter.Writer)
+	encodings sync.Map // map[config]*encoding
+}
+
+// config is the part of a writer that affects its output.
+type config struct {
+	flags        jwriter.Flags
+	noEscapeHTML bool
+}
+
+type encoding struct {
+	once sync.Once
+	data []byte
+	ok   bool
+}
+
+// Fields returns the cache of the members written by write.
+func Fields(write func(w *jsoni.ObjectWriter)) *Cache {
+	return &Cache{write: func(w *jwriter.Writer) {
+		// An object writer that is never opened writes the members as they are.
+		obj := jsoni.NewObjectWriter(w)
+		write(&obj)
+	}}
+}
+
+// Value returns the cache of the value written by write.
+func Value(write func(w *jsoni.ArrayWriter)) *Cache {
+	return &Cache{write: func(w *jwriter.Writer) {
+		// An array writer that is never opened writes the value as it is.
+		arr := jsoni.NewArrayWriter(w)
+		write(&arr)
+	}}
+}
+
+// WriteFields writes the cached members to w. It reports false, writing nothing, if the subtree
+// fails to encode, in which case it is not cached and must be written normally.
+func (c *Cache) WriteFields(w *jsoni.ObjectWriter) bool {
+	data, ok := c.get(hooks.Writer(w))
+	if ok {
+		w.RawFields(data)
+	}
+	return ok
+}
+
+// WriteValue writes the cached value to w, like WriteFields.
+func (c *Cache) WriteValue(w *jsoni.ArrayWriter) bool {
+	data, ok := c.get(hooks.Writer(w))
+	if ok && len(data) > 0 {
+		w.RawValue(data)
+	}
+	return ok
+}
+
+// get returns the encoding for writers configured like w, encoding the subtree on a writer of
+// its own on the first call. The encoding is kept unless writing failed.
+func (c *Cache) get(w *jwriter.Writer) ([]byte, bool) {
+	key := config{w.Flags, w.NoEscapeHTML}
+	e, ok := c.encodings.Load(key)
+	if !ok {
+		e, _ = c.encodings.LoadOrStore(key, &encoding{})
+	}
+	enc := e.(*encoding)
+	enc.once.Do(func() {
+		inner := jwriter.Writer{Flags: key.flags, NoEscapeHTML: key.noEscapeHTML}
+		c.write(&inner)
+		data, err := inner.BuildBytes()
+		enc.data, enc.ok = data, err == nil
+	})
+	return enc.data, enc.ok
+}
@@ -0,0 +1,12 @@
+// Package hooks gives the other packages of the module parts of jsoni that are not part of its
+// API. They are set when jsoni is initialized.
+package hooks
+
+import "github.com/mailru/easyjson/jwriter"
+
+// Encode writes value to writer like encoding/json writes it, setting the error of the writer
+// instead if it cannot be represented.
+var Encode func(writer *jwriter.Writer, value any)
+
+// Writer returns the underlying writer of a *jsoni.ObjectWriter or *jsoni.ArrayWriter.
+var Writer func(w any) *jwriter.Writer
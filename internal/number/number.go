@@ -0,0 +1,45 @@
+// Package number checks the number literals written as they are, so that every package
+// accepts exactly the same ones.
+package number
+
+// Valid reports whether the literal matches the JSON number grammar.
+func Valid(literal string) bool {
+	i := 0
+	if i < len(literal) && literal[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(literal) && literal[i] == '0':
+		i++
+	case i < len(literal) && literal[i] >= '1' && literal[i] <= '9':
+		i = skipDigits(literal, i+1)
+	default:
+		return false
+	}
+	if i < len(literal) && literal[i] == '.' {
+		j := skipDigits(literal, i+1)
+		if j == i+1 {
+			return false
+		}
+		i = j
+	}
+	if i < len(literal) && (literal[i] == 'e' || literal[i] == 'E') {
+		i++
+		if i < len(literal) && (literal[i] == '+' || literal[i] == '-') {
+			i++
+		}
+		j := skipDigits(literal, i)
+		if j == i {
+			return false
+		}
+		i = j
+	}
+	return i == len(literal)
+}
+
+func skipDigits(literal string, i int) int {
+	for i < len(literal) && literal[i] >= '0' && literal[i] <= '9' {
+		i++
+	}
+	return i
+}
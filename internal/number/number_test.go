@@ -0,0 +1,19 @@
+package number
+
+import "testing"
+
+func TestValid(t *testing.T) {
+	valid := []string{"0", "-0", "1", "-1", "42", "0.5", "-0.5", "19.99", "1e5", "1E5", "2.5e-3", "1.23e+4", "23565849841318736104"}
+	invalid := []string{"", "-", "+1", "01", "1.", ".5", "1e", "1e+", "0x10", "NaN", "Infinity", "1 ", " 1", "1,5", "--1"}
+
+	for _, value := range valid {
+		if !Valid(value) {
+			t.Errorf("Expected %q to be valid", value)
+		}
+	}
+	for _, value := range invalid {
+		if Valid(value) {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Package sorted orders map keys for the packages writing maps.
+package sorted
+
+import "sort"
+
+// Keys returns the keys of a map in the order encoding/json writes them.
+func Keys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
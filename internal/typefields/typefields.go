@@ -0,0 +1,192 @@
+// Package typefields resolves the fields encoding/json writes for a struct type. It is shared
+// by the reflection encoder of jsoni and by jsonwgen, which see types through reflect and
+// go/types respectively.
+package typefields
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Member is a field of a struct type, as far as encoding/json is concerned.
+type Member[T comparable] struct {
+	Name     string
+	Tag      string // the json tag of the field
+	Exported bool
+	Embedded bool
+	Type     T
+	// Elem is the type of the field, or its element type if it is an unnamed pointer type,
+	// which is looked through for embedding and the string option.
+	Elem T
+	// Struct reports whether Elem is a struct type.
+	Struct bool
+	// Scalar reports whether Elem is a boolean, number or string type,
+	// the only ones the string option applies to.
+	Scalar bool
+}
+
+// Field is a field written by encoding/json.
+type Field[T comparable] struct {
+	Name      string // the JSON key
+	Index     []int  // the field index sequence, through embedded structs
+	Type      T
+	Tagged    bool
+	OmitEmpty bool
+	Quoted    bool
+}
+
+// Resolve returns the fields encoding/json writes for the struct type t, in field order,
+// resolving the names promoted from embedded structs with the same rules. The members of
+// t and of the struct types embedded in it are listed by members.
+func Resolve[T comparable](t T, members func(T) []Member[T]) []Field[T] {
+	type embedded struct {
+		typ   T
+		index []int
+	}
+
+	var current []embedded
+	next := []embedded{{typ: t}}
+
+	var count, nextCount map[T]int
+	visited := map[T]bool{}
+
+	var fields []Field[T]
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[T]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i, m := range members(e.typ) {
+				if m.Embedded {
+					if !m.Exported && !m.Struct {
+						continue
+					}
+				} else if !m.Exported {
+					continue
+				}
+
+				if m.Tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(m.Tag, ",")
+				if !isValidTag(name) {
+					name = ""
+				}
+
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				if name != "" || !m.Embedded || !m.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = m.Name
+					}
+					field := Field[T]{
+						Name:      name,
+						Index:     index,
+						Type:      m.Type,
+						Tagged:    tagged,
+						OmitEmpty: hasOption(opts, "omitempty"),
+						Quoted:    m.Scalar && hasOption(opts, "string"),
+					}
+					fields = append(fields, field)
+					if count[e.typ] > 1 {
+						// The same embedded type was reached more than once at this depth,
+						// so a duplicate makes the conflict resolution below drop the field.
+						fields = append(fields, field)
+					}
+					continue
+				}
+
+				nextCount[m.Elem]++
+				if nextCount[m.Elem] == 1 {
+					next = append(next, embedded{typ: m.Elem, index: index})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x := fields
+		if x[i].Name != x[j].Name {
+			return x[i].Name < x[j].Name
+		}
+		if len(x[i].Index) != len(x[j].Index) {
+			return len(x[i].Index) < len(x[j].Index)
+		}
+		if x[i].Tagged != x[j].Tagged {
+			return x[i].Tagged
+		}
+		return lessIndex(x[i].Index, x[j].Index)
+	})
+
+	// Among fields sharing a name, the shallowest one wins, then the tagged one;
+	// if that still leaves a tie, the name is dropped altogether.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].Name != fi.Name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		if dominant := fields[i : i+advance]; len(dominant[0].Index) != len(dominant[1].Index) || dominant[0].Tagged != dominant[1].Tagged {
+			out = append(out, dominant[0])
+		}
+	}
+
+	fields = out
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].Index, fields[j].Index)
+	})
+	return fields
+}
+
+func lessIndex(a, b []int) bool {
+	for k, x := range a {
+		if k >= len(b) {
+			return false
+		}
+		if x != b[k] {
+			return x < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var name string
+		name, opts, _ = strings.Cut(opts, ",")
+		if name == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidTag reports whether the tag name is accepted by encoding/json.
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package jsond
+
+import (
+	"fmt"
+
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// FieldNode is the constraint on the field type of a declarative package.
+type FieldNode interface {
+	WriteField(writer *jsoni.ObjectWriter)
+}
+
+// ValueNode is the constraint on the value type of a declarative package.
+type ValueNode interface {
+	WriteValue(writer *jsoni.ArrayWriter)
+}
+
+// ObjectRoot is the constraint on the root object type of a declarative package.
+type ObjectRoot interface {
+	Build() ([]byte, error)
+	BuildInto(w *jwriter.Writer) error
+	WriteFields(writer *jsoni.ObjectWriter)
+	Describe() Node
+}
+
+// ArrayRoot is the constraint on the root array type of a declarative package.
+type ArrayRoot interface {
+	Build() ([]byte, error)
+	BuildInto(w *jwriter.Writer) error
+	WriteValues(writer *jsoni.ArrayWriter)
+	Describe() Node
+}
+
+// API is the constructor set of a declarative package with field type F, value type V,
+// root object type O and root array type A. A package declares it by assigning its
+// constructors, so that any difference in their signatures fails to compile.
+type API[F FieldNode, V ValueNode, O ObjectRoot, A ArrayRoot] struct {
+	Name string // the package name
+
+	New      func(fields ...F) O
+	NewArray func(values ...V) A
+
+	Object   func(name string, fields ...F) F
+	Array    func(name string, values ...V) F
+	String   func(name, value string) F
+	Number   func(name, value string) F
+	Integer  func(name string, value int64) F
+	Float    func(name string, value float64) F
+	Boolean  func(name string, value bool) F
+	Null     func(name string) F
+	Any      func(name string, value any) F
+	ObjectOf func(name string, value jsoni.ObjectMarshaler) F
+	ValueOf  func(name string, value jsoni.ValueMarshaler) F
+	Custom   func(name string, fn func(writer *jsoni.ObjectWriter)) F
+	Frozen   func(field F) F
+
+	ObjectItem   func(fields ...F) V
+	ArrayItem    func(values ...V) V
+	StringItem   func(value string) V
+	NumberItem   func(value string) V
+	IntegerItem  func(value int64) V
+	FloatItem    func(value float64) V
+	BooleanItem  func(value bool) V
+	NullItem     func() V
+	AnyItem      func(value any) V
+	ObjectOfItem func(value jsoni.ObjectMarshaler) V
+	ValueOfItem  func(value jsoni.ValueMarshaler) V
+	CustomItem   func(fn func(writer *jsoni.ArrayWriter)) V
+	FrozenItem   func(value V) V
+
+	Describe     func(field F) Node
+	DescribeItem func(value V) Node
+}
+
+// Field returns the field built by the package for the node n.
+func (api API[F, V, O, A]) Field(n Node) F {
+	switch n.Kind {
+	case Object:
+		return api.Object(n.Name, api.fields(n.Children)...)
+	case Array:
+		return api.Array(n.Name, api.values(n.Children)...)
+	case String:
+		return api.String(n.Name, n.Value.(string))
+	case Number:
+		return api.Number(n.Name, n.Value.(string))
+	case Integer:
+		return api.Integer(n.Name, n.Value.(int64))
+	case Float:
+		return api.Float(n.Name, n.Value.(float64))
+	case Boolean:
+		return api.Boolean(n.Name, n.Value.(bool))
+	case Null:
+		return api.Null(n.Name)
+	case Any:
+		return api.Any(n.Name, n.Value)
+	case ObjectOf:
+		return api.ObjectOf(n.Name, n.Value.(jsoni.ObjectMarshaler))
+	case ValueOf:
+		return api.ValueOf(n.Name, n.Value.(jsoni.ValueMarshaler))
+	case Custom:
+		return api.Custom(n.Name, n.Value.(func(writer *jsoni.ObjectWriter)))
+	}
+	panic(fmt.Sprintf("jsond: invalid node kind %d", n.Kind))
+}
+
+// Value returns the value built by the package for the node n.
+func (api API[F, V, O, A]) Value(n Node) V {
+	switch n.Kind {
+	case Object:
+		return api.ObjectItem(api.fields(n.Children)...)
+	case Array:
+		return api.ArrayItem(api.values(n.Children)...)
+	case String:
+		return api.StringItem(n.Value.(string))
+	case Number:
+		return api.NumberItem(n.Value.(string))
+	case Integer:
+		return api.IntegerItem(n.Value.(int64))
+	case Float:
+		return api.FloatItem(n.Value.(float64))
+	case Boolean:
+		return api.BooleanItem(n.Value.(bool))
+	case Null:
+		return api.NullItem()
+	case Any:
+		return api.AnyItem(n.Value)
+	case ObjectOf:
+		return api.ObjectOfItem(n.Value.(jsoni.ObjectMarshaler))
+	case ValueOf:
+		return api.ValueOfItem(n.Value.(jsoni.ValueMarshaler))
+	case Custom:
+		return api.CustomItem(n.Value.(func(writer *jsoni.ArrayWriter)))
+	}
+	panic(fmt.Sprintf("jsond: invalid node kind %d", n.Kind))
+}
+
+func (api API[F, V, O, A]) fields(nodes []Node) []F {
+	fields := make([]F, len(nodes))
+	for i, n := range nodes {
+		fields[i] = api.Field(n)
+	}
+	return fields
+}
+
+func (api API[F, V, O, A]) values(nodes []Node) []V {
+	values := make([]V, len(nodes))
+	for i, n := range nodes {
+		values[i] = api.Value(n)
+	}
+	return values
+}
+
+// RootObject returns the root object built by the package with the members of the Object node n.
+func (api API[F, V, O, A]) RootObject(n Node) O {
+	return api.New(api.fields(n.Children)...)
+}
+
+// RootArray returns the root array built by the package with the values of the Array node n.
+func (api API[F, V, O, A]) RootArray(n Node) A {
+	return api.NewArray(api.values(n.Children)...)
+}
+
+// Build builds the document whose root is the Object or Array node root.
+func (api API[F, V, O, A]) Build(root Node) ([]byte, error) {
+	switch root.Kind {
+	case Object:
+		return api.RootObject(root).Build()
+	case Array:
+		return api.RootArray(root).Build()
+	}
+	return nil, fmt.Errorf("jsond: the root must be an object or an array, not kind %d", root.Kind)
+}
+
+// describe builds the root node with the package and returns the description of the built tree.
+func (api API[F, V, O, A]) describe(root Node) (Node, error) {
+	switch root.Kind {
+	case Object:
+		return api.RootObject(root).Describe(), nil
+	case Array:
+		return api.RootArray(root).Describe(), nil
+	}
+	return Node{}, fmt.Errorf("jsond: the root must be an object or an array, not kind %d", root.Kind)
+}
+
+// Implementation returns the package as a registrable Implementation.
+func (api API[F, V, O, A]) Implementation() Implementation {
+	return Implementation{Name: api.Name, Build: api.Build, Describe: api.describe}
+}
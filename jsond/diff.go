@@ -0,0 +1,286 @@
+package jsond
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// Builder is a document that builds into JSON, such as a root of the declarative packages.
+type Builder interface {
+	Build() ([]byte, error)
+}
+
+// DiffOptions change how Diff compares documents.
+type DiffOptions struct {
+	// UnorderedKeys compares object members by name only. By default the order of members matters,
+	// and members out of place are removed and added again, after the ones that stay in place.
+	UnorderedKeys bool
+	// NumericNumbers compares numbers by value, so that 1, 1.0 and 1e0 are equal.
+	// By default numbers are compared by their literal, as they are written.
+	NumericNumbers bool
+}
+
+// Diff builds the documents a and b, and returns the JSON Patch (RFC 6902) turning the first into
+// the second, as a JSON array of operations, which is empty if they are equal. Anything the trees
+// write is compared, including Any, marshaler and Custom nodes.
+func Diff(a, b Builder, opts DiffOptions) ([]byte, error) {
+	from, err := a.Build()
+	if err != nil {
+		return nil, err
+	}
+	to, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return DiffBytes(from, to, opts)
+}
+
+// DiffBytes returns the JSON Patch turning the JSON document a into b, like Diff.
+func DiffBytes(a, b []byte, opts DiffOptions) ([]byte, error) {
+	from, err := parse(a)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	d := differ{opts: opts}
+	d.diff("", &from, &to)
+
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	for _, op := range d.ops {
+		writer.Object(func(obj *jsoni.ObjectWriter) {
+			obj.StringField("op", op.op)
+			obj.StringField("path", op.path)
+			if op.value != nil {
+				obj.AnyField("value", op.value)
+			}
+		})
+	}
+	writer.Close()
+	return writer.BuildBytes()
+}
+
+// operation is an operation of a JSON Patch.
+type operation struct {
+	op    string
+	path  string
+	value json.RawMessage
+}
+
+type differ struct {
+	opts DiffOptions
+	ops  []operation
+}
+
+func (d *differ) add(path string, n *value) {
+	d.ops = append(d.ops, operation{"add", path, n.raw})
+}
+
+func (d *differ) remove(path string) {
+	d.ops = append(d.ops, operation{"remove", path, nil})
+}
+
+// diff appends the operations turning a into b, both at path.
+func (d *differ) diff(path string, a, b *value) {
+	if a.kind != b.kind {
+		d.ops = append(d.ops, operation{"replace", path, b.raw})
+		return
+	}
+	switch a.kind {
+	case '{':
+		if d.opts.UnorderedKeys {
+			d.unordered(path, a, b)
+		} else {
+			d.ordered(path, a, b)
+		}
+	case '[':
+		n := min(len(a.children), len(b.children))
+		for i := 0; i < n; i++ {
+			d.diff(path+"/"+strconv.Itoa(i), &a.children[i], &b.children[i])
+		}
+		for i := n; i < len(b.children); i++ {
+			d.add(path+"/-", &b.children[i])
+		}
+		// Elements are removed from the end, so that the indexes of the others don't change.
+		for i := len(a.children) - 1; i >= n; i-- {
+			d.remove(path + "/" + strconv.Itoa(i))
+		}
+	default:
+		if !d.equal(a, b) {
+			d.ops = append(d.ops, operation{"replace", path, b.raw})
+		}
+	}
+}
+
+// unordered diffs the members of two objects by name.
+func (d *differ) unordered(path string, a, b *value) {
+	names := make(map[string]int, len(b.names))
+	for j, name := range b.names {
+		names[name] = j
+	}
+	found := make(map[string]bool, len(a.names))
+	for i, name := range a.names {
+		found[name] = true
+		if j, ok := names[name]; ok {
+			d.diff(path+"/"+escape(name), &a.children[i], &b.children[j])
+		} else {
+			d.remove(path + "/" + escape(name))
+		}
+	}
+	for j, name := range b.names {
+		if !found[name] {
+			d.add(path+"/"+escape(name), &b.children[j])
+		}
+	}
+}
+
+// ordered diffs the members of two objects, keeping the longest run of members in the same order
+// in place. The add operation appends new members, so the members of b following the first one
+// that is not kept are removed and added again, in order.
+func (d *differ) ordered(path string, a, b *value) {
+	kept := lcs(a.names, b.names)
+	next := 0 // the first member of b that is not kept
+	for next < len(b.names) && next < len(kept) && kept[next][1] == next {
+		next++
+	}
+	if next < len(kept) {
+		kept = kept[:next]
+	}
+
+	stays := make([]bool, len(a.names))
+	for _, pair := range kept {
+		stays[pair[0]] = true
+	}
+	for i, name := range a.names {
+		if !stays[i] {
+			d.remove(path + "/" + escape(name))
+		}
+	}
+	for _, pair := range kept {
+		d.diff(path+"/"+escape(b.names[pair[1]]), &a.children[pair[0]], &b.children[pair[1]])
+	}
+	for j := next; j < len(b.names); j++ {
+		d.add(path+"/"+escape(b.names[j]), &b.children[j])
+	}
+}
+
+// equal reports whether two scalars of the same kind are equal.
+func (d *differ) equal(a, b *value) bool {
+	if a.kind == '0' && d.opts.NumericNumbers {
+		x, okx := new(big.Rat).SetString(a.text)
+		y, oky := new(big.Rat).SetString(b.text)
+		if okx && oky {
+			return x.Cmp(y) == 0
+		}
+	}
+	return a.text == b.text
+}
+
+// lcs returns the index pairs of a longest common subsequence of a and b, in order.
+func lcs(a, b []string) [][2]int {
+	// A common prefix is kept as it is, which is the usual case.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	pairs := make([][2]int, prefix, max(len(a), len(b)))
+	for i := range pairs {
+		pairs[i] = [2]int{i, i}
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{prefix + i, prefix + j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// value is a parsed JSON value, which keeps the order of object members and the literal of numbers.
+type value struct {
+	kind     byte // '{', '[', '"', '0' for numbers, or 'l' for true, false and null
+	raw      json.RawMessage
+	text     string   // the unescaped string, the number or the literal
+	names    []string // the member names of an object
+	children []value  // the member values of an object, or the values of an array
+}
+
+func parse(data []byte) (value, error) {
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		return value{}, fmt.Errorf("jsond: invalid JSON: %.40q", data)
+	}
+	return parseValue(data)
+}
+
+// parseValue parses valid JSON without surrounding whitespace.
+func parseValue(data []byte) (value, error) {
+	v := value{raw: data}
+	switch data[0] {
+	case '{', '[':
+		v.kind = data[0]
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if _, err := dec.Token(); err != nil {
+			return v, err
+		}
+		for dec.More() {
+			if v.kind == '{' {
+				name, err := dec.Token()
+				if err != nil {
+					return v, err
+				}
+				v.names = append(v.names, name.(string))
+			}
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return v, err
+			}
+			child, err := parseValue(bytes.TrimSpace(raw))
+			if err != nil {
+				return v, err
+			}
+			v.children = append(v.children, child)
+		}
+	case '"':
+		v.kind = '"'
+		if err := json.Unmarshal(data, &v.text); err != nil {
+			return v, err
+		}
+	case 't', 'f', 'n':
+		v.kind, v.text = 'l', string(data)
+	default:
+		v.kind, v.text = '0', string(data)
+	}
+	return v, nil
+}
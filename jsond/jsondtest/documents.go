@@ -0,0 +1,194 @@
+// Package jsondtest is the conformance suite of the declarative packages.
+//
+// It builds a shared battery of documents with every implementation registered with jsond,
+// and checks that each writes the same bytes and reports the same errors as the jsoni
+// writers would, when used directly.
+package jsondtest
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// Document is a named document of the battery.
+type Document struct {
+	Name string
+	Root jsond.Node // an Object or Array node
+}
+
+// Documents returns the battery of documents, covering edge-case strings and numbers,
+// deep nesting, empty containers, marshalers, user-defined nodes and write errors.
+func Documents() []Document {
+	return []Document{
+		{"empty object", object()},
+		{"empty array", array()},
+		{"strings", object(
+			member("empty", str("")),
+			member("quotes", str(`"quoted" \ back\slash /slash`)),
+			member("controls", str("\x00\x01\b\f\n\r\t\x1f\x7f")),
+			member("html", str("<script>&amp;</script>")),
+			member("separators", str("  ")),
+			member("invalid", str("a\xffb\xc3")),
+			member("unicode", str("héllo, 世界 🎉")),
+			member(`escaped\"name`, str("member names are written as they are")),
+			member("", str("empty name")),
+		)},
+		{"numbers", object(
+			member("zero", integer(0)),
+			member("min", integer(math.MinInt64)),
+			member("max", integer(math.MaxInt64)),
+			member("negative zero", float(math.Copysign(0, -1))),
+			member("tenth", float(0.1)),
+			member("large", float(1e21)),
+			member("small", float(1e-7)),
+			member("max float", float(math.MaxFloat64)),
+			member("smallest", float(math.SmallestNonzeroFloat64)),
+			member("integral", float(42)),
+			member("literal", number("12345678901234567890.5e-3")),
+			member("negative literal", number("-0.0")),
+			member("exponent", number("1E+400")),
+		)},
+		{"literals", array(
+			boolean(true),
+			boolean(false),
+			null(),
+			array(null()),
+			object(member("true", boolean(true)), member("null", null())),
+		)},
+		{"empty containers", object(
+			member("object", object()),
+			member("array", array()),
+			member("nested", array(array(), object(), array(array()))),
+		)},
+		{"deep nesting", array(nested(64))},
+		{"any", object(
+			member("nil", anyValue(nil)),
+			member("map", anyValue(map[string]any{"b": []int{1, 2}, "a": "x"})),
+			member("struct", anyValue(struct {
+				Name string `json:"name"`
+				Tags []string
+				Skip bool `json:"-"`
+			}{"n", nil, true})),
+			member("bytes", anyValue([]byte("jsonw"))),
+			member("float", anyValue(1.5)),
+			member("values", array(anyValue(nil), anyValue([]any{"a", 1, true}))),
+		)},
+		{"marshalers", array(
+			objectOf(point{1, 2}),
+			valueOf(pair{3, 4}),
+			object(member("object", objectOf(point{5, 6})), member("value", valueOf(pair{7, 8}))),
+			anyValue(point{9, 10}),
+			anyValue(pair{11, 12}),
+		)},
+		{"custom", object(
+			custom("custom", func(w *jsoni.ObjectWriter) {
+				w.Array("custom", func(arr *jsoni.ArrayWriter) {
+					arr.StringValue("written at build time")
+				})
+			}),
+			member("items", array(customItem(func(w *jsoni.ArrayWriter) {
+				w.IntegerValue(1)
+				w.IntegerValue(2)
+			}))),
+		)},
+		{"unsupported float", object(
+			member("before", str("kept")),
+			member("nan", float(math.NaN())),
+			member("after", str("still written")),
+		)},
+		{"infinite float item", array(integer(1), float(math.Inf(-1)))},
+		{"invalid number", object(member("nested", object(member("number", number("01")))))},
+		{"unsupported any", array(object(member("channel", anyValue(make(chan int)))))},
+	}
+}
+
+// point is a marshaler writing itself as an object.
+type point struct{ x, y int64 }
+
+func (p point) WriteJSONObject(w *jsoni.ObjectWriter) {
+	w.IntegerField("x", p.x)
+	w.IntegerField("y", p.y)
+}
+
+// pair is a marshaler writing itself as an array.
+type pair point
+
+func (p pair) WriteJSONValue(w *jsoni.ArrayWriter) {
+	arr := w.ArrayValue()
+	arr.Open()
+	arr.IntegerValue(p.x)
+	arr.IntegerValue(p.y)
+	arr.Close()
+}
+
+// member names the node, making it an object member.
+func member(name string, n jsond.Node) jsond.Node {
+	n.Name = name
+	return n
+}
+
+func object(members ...jsond.Node) jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Children: members}
+}
+
+func array(values ...jsond.Node) jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Children: values}
+}
+
+func str(value string) jsond.Node {
+	return jsond.Node{Kind: jsond.String, Value: value}
+}
+
+func number(value string) jsond.Node {
+	return jsond.Node{Kind: jsond.Number, Value: value}
+}
+
+func integer(value int64) jsond.Node {
+	return jsond.Node{Kind: jsond.Integer, Value: value}
+}
+
+func float(value float64) jsond.Node {
+	return jsond.Node{Kind: jsond.Float, Value: value}
+}
+
+func boolean(value bool) jsond.Node {
+	return jsond.Node{Kind: jsond.Boolean, Value: value}
+}
+
+func null() jsond.Node {
+	return jsond.Node{Kind: jsond.Null}
+}
+
+func anyValue(value any) jsond.Node {
+	return jsond.Node{Kind: jsond.Any, Value: value}
+}
+
+func objectOf(value jsoni.ObjectMarshaler) jsond.Node {
+	return jsond.Node{Kind: jsond.ObjectOf, Value: value}
+}
+
+func valueOf(value jsoni.ValueMarshaler) jsond.Node {
+	return jsond.Node{Kind: jsond.ValueOf, Value: value}
+}
+
+func custom(name string, fn func(w *jsoni.ObjectWriter)) jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: name, Value: fn}
+}
+
+func customItem(fn func(w *jsoni.ArrayWriter)) jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: fn}
+}
+
+// nested returns depth levels of alternating arrays and objects.
+func nested(depth int) jsond.Node {
+	switch {
+	case depth == 0:
+		return str("bottom")
+	case depth%2 == 0:
+		return array(nested(depth - 1))
+	}
+	return object(member("level"+strconv.Itoa(depth), nested(depth-1)))
+}
@@ -0,0 +1,184 @@
+package jsondtest
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Reference writes the document whose root is the Object or Array node root with the
+// jsoni writers directly, which is what every implementation must match.
+func Reference(root jsond.Node) ([]byte, error) {
+	w := jwriter.Writer{}
+	switch root.Kind {
+	case jsond.Object:
+		writer := jsoni.NewObjectWriter(&w)
+		writer.Open()
+		writeMembers(&writer, root.Children)
+		writer.Close()
+		return writer.BuildBytes()
+	case jsond.Array:
+		writer := jsoni.NewArrayWriter(&w)
+		writer.Open()
+		writeValues(&writer, root.Children)
+		writer.Close()
+		return writer.BuildBytes()
+	}
+	return nil, fmt.Errorf("jsondtest: the root must be an object or an array, not kind %d", root.Kind)
+}
+
+func writeMembers(w *jsoni.ObjectWriter, nodes []jsond.Node) {
+	for _, n := range nodes {
+		switch n.Kind {
+		case jsond.Object:
+			w.Object(n.Name, func(obj *jsoni.ObjectWriter) {
+				writeMembers(obj, n.Children)
+			})
+		case jsond.Array:
+			w.Array(n.Name, func(arr *jsoni.ArrayWriter) {
+				writeValues(arr, n.Children)
+			})
+		case jsond.String:
+			w.StringField(n.Name, n.Value.(string))
+		case jsond.Number:
+			w.NumberField(n.Name, n.Value.(string))
+		case jsond.Integer:
+			w.IntegerField(n.Name, n.Value.(int64))
+		case jsond.Float:
+			w.FloatField(n.Name, n.Value.(float64))
+		case jsond.Boolean:
+			w.BooleanField(n.Name, n.Value.(bool))
+		case jsond.Null:
+			w.NullField(n.Name)
+		case jsond.Any, jsond.ObjectOf, jsond.ValueOf:
+			w.AnyField(n.Name, n.Value)
+		case jsond.Custom:
+			n.Value.(func(writer *jsoni.ObjectWriter))(w)
+		}
+	}
+}
+
+func writeValues(w *jsoni.ArrayWriter, nodes []jsond.Node) {
+	for _, n := range nodes {
+		switch n.Kind {
+		case jsond.Object:
+			w.Object(func(obj *jsoni.ObjectWriter) {
+				writeMembers(obj, n.Children)
+			})
+		case jsond.Array:
+			w.Array(func(arr *jsoni.ArrayWriter) {
+				writeValues(arr, n.Children)
+			})
+		case jsond.String:
+			w.StringValue(n.Value.(string))
+		case jsond.Number:
+			w.NumberValue(n.Value.(string))
+		case jsond.Integer:
+			w.IntegerValue(n.Value.(int64))
+		case jsond.Float:
+			w.FloatValue(n.Value.(float64))
+		case jsond.Boolean:
+			w.BooleanValue(n.Value.(bool))
+		case jsond.Null:
+			w.NullValue()
+		case jsond.Any, jsond.ObjectOf, jsond.ValueOf:
+			w.AnyValue(n.Value)
+		case jsond.Custom:
+			n.Value.(func(writer *jsoni.ArrayWriter))(w)
+		}
+	}
+}
+
+// Run checks that impl builds every document of the battery like Reference,
+// and that it describes the built trees as the nodes they were built from.
+func Run(t *testing.T, impl jsond.Implementation) {
+	t.Helper()
+	for _, doc := range Documents() {
+		doc := doc
+		t.Run(doc.Name, func(t *testing.T) {
+			want, wantErr := Reference(doc.Root)
+			got, err := impl.Build(doc.Root)
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s wrote\n%s\nwant\n%s", impl.Name, got, want)
+			}
+			if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+				t.Errorf("%s returned error %v, want %v", impl.Name, err, wantErr)
+			}
+
+			described, err := impl.Describe(doc.Root)
+			if err != nil {
+				t.Fatalf("%s failed to describe: %v", impl.Name, err)
+			}
+			if path, ok := equal(described, doc.Root, ""); !ok {
+				t.Errorf("%s described %s differently", impl.Name, pathOrRoot(path))
+			}
+		})
+	}
+}
+
+// RunAll runs the suite for every implementation registered with jsond,
+// failing if there is none.
+func RunAll(t *testing.T) {
+	t.Helper()
+	impls := jsond.Implementations()
+	if len(impls) == 0 {
+		t.Fatal("no declarative implementation is registered")
+	}
+	for _, impl := range impls {
+		impl := impl
+		t.Run(impl.Name, func(t *testing.T) {
+			Run(t, impl)
+		})
+	}
+}
+
+// equal reports whether the nodes a and b are equal, and if not, the path of the first difference.
+// Custom nodes only need to have the same name and a function of the same type,
+// since functions can't be compared.
+func equal(a, b jsond.Node, path string) (string, bool) {
+	if a.Kind != b.Kind || a.Name != b.Name || len(a.Children) != len(b.Children) {
+		return path, false
+	}
+	switch a.Kind {
+	case jsond.Object:
+		for i := range a.Children {
+			if p, ok := equal(a.Children[i], b.Children[i], path+"/"+a.Children[i].Name); !ok {
+				return p, false
+			}
+		}
+	case jsond.Array:
+		for i := range a.Children {
+			if p, ok := equal(a.Children[i], b.Children[i], path+"/"+strconv.Itoa(i)); !ok {
+				return p, false
+			}
+		}
+	case jsond.Float:
+		x, y := a.Value.(float64), b.Value.(float64)
+		if math.Float64bits(x) != math.Float64bits(y) && !(math.IsNaN(x) && math.IsNaN(y)) {
+			return path, false
+		}
+	case jsond.Custom:
+		if a.Value == nil || reflect.TypeOf(a.Value) != reflect.TypeOf(b.Value) {
+			return path, false
+		}
+	default:
+		if !reflect.DeepEqual(a.Value, b.Value) {
+			return path, false
+		}
+	}
+	return "", true
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "the root"
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+package jsond
+
+// Merge returns target with the JSON Merge Patch (RFC 7396) patch applied, without modifying either.
+// If patch is an Object, its Null members remove the members of the same name from target, and its
+// other members are merged into them recursively, or appended in the order of patch; if target is
+// not an Object, it is merged as an empty one. Any other patch replaces target as a whole, including
+// the Any, marshaler and Custom nodes, whose content is only known once written.
+// Custom members without a name, such as functions used as fields, write members of unknown names,
+// so they never match another member: those of patch are appended, and those of target are kept.
+// The result has the name of target.
+func Merge(target, patch Node) Node {
+	merged := merge(&target, patch)
+	merged.Name = target.Name
+	return merged
+}
+
+// merge applies patch to target, which is nil if the member to patch is missing.
+func merge(target *Node, patch Node) Node {
+	if patch.Kind != Object {
+		return patch
+	}
+
+	result := Node{Kind: Object, Name: patch.Name}
+	if target != nil && target.Kind == Object {
+		result.Children = append([]Node{}, target.Children...)
+	}
+	for _, member := range patch.Children {
+		i := -1
+		if !unnamed(member) {
+			i = indexOf(result.Children, member.Name)
+		}
+		switch {
+		case member.Kind == Null:
+			if i >= 0 {
+				result.Children = append(result.Children[:i], result.Children[i+1:]...)
+			}
+		case i >= 0:
+			result.Children[i] = merge(&result.Children[i], member)
+		default:
+			result.Children = append(result.Children, merge(nil, member))
+		}
+	}
+	return result
+}
+
+// indexOf returns the index of the first member named name, other than an unnamed Custom one, or -1.
+func indexOf(members []Node, name string) int {
+	for i := range members {
+		if members[i].Name == name && !unnamed(members[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unnamed reports whether n is a Custom member whose name is unknown.
+func unnamed(n Node) bool {
+	return n.Kind == Custom && n.Name == ""
+}
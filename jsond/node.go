@@ -0,0 +1,62 @@
+// Package jsond describes the API shared by the declarative packages jsondf, jsondi and jsonds.
+//
+// API lists the constructors every declarative package provides, so that a package that
+// doesn't match the contract fails to compile where it declares its API. Node describes a
+// document independently of the package that builds it, and the packages register an
+// Implementation building such documents, which the jsondtest package checks for conformance.
+package jsond
+
+import "strconv"
+
+// Kind is the kind of a Node, matching one constructor of the declarative API.
+type Kind uint8
+
+const (
+	Object Kind = iota
+	Array
+	String
+	Number
+	Integer
+	Float
+	Boolean
+	Null
+	Any
+	ObjectOf
+	ValueOf
+	Custom
+)
+
+var kindNames = [...]string{
+	Object:   "Object",
+	Array:    "Array",
+	String:   "String",
+	Number:   "Number",
+	Integer:  "Integer",
+	Float:    "Float",
+	Boolean:  "Boolean",
+	Null:     "Null",
+	Any:      "Any",
+	ObjectOf: "ObjectOf",
+	ValueOf:  "ValueOf",
+	Custom:   "Custom",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Node is a document node independent of the declarative package that builds it.
+// It is an object member when it is a child of an Object node, and an array value otherwise.
+//
+// The declarative packages also describe their trees as nodes, so that they can be inspected or
+// walked without writing them. Nodes whose content is only produced when they are written, such as
+// those of Each or Frozen, are described as Custom nodes whose Value writes them.
+type Node struct {
+	Kind     Kind
+	Name     string // the member name, for object members
+	Children []Node // the members of an Object, or the values of an Array
+	Value    any    // the value of a scalar, marshaler or custom node, as passed to its constructor
+}
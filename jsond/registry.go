@@ -0,0 +1,47 @@
+package jsond
+
+import (
+	"sort"
+	"sync"
+)
+
+// Implementation is a declarative package, reduced to building documents described by nodes.
+type Implementation struct {
+	Name  string
+	Build func(root Node) ([]byte, error)
+	// Describe builds a document like Build, and returns the description of the built tree.
+	Describe func(root Node) (Node, error)
+}
+
+var (
+	mu              sync.RWMutex
+	implementations = map[string]Implementation{}
+)
+
+// Register makes an implementation available to Implementations.
+// The declarative packages register themselves when they are imported.
+// It panics if an implementation with the same name is already registered.
+func Register(impl Implementation) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := implementations[impl.Name]; ok {
+		panic("jsond: Register called twice for " + impl.Name)
+	}
+	implementations[impl.Name] = impl
+}
+
+// Implementations returns the registered implementations, sorted by name.
+func Implementations() []Implementation {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]Implementation, 0, len(implementations))
+	for _, impl := range implementations {
+		result = append(result, impl)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,61 @@
+package jsond
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Visitor receives the nodes of a document walked by Walk, each with its path: the JSON Pointer
+// (RFC 6901) of the node, which is "" for the root and "/tags/0" for the first value of the
+// member tags.
+type Visitor interface {
+	// EnterObject is called for an Object node before its members, which are skipped if it returns false.
+	EnterObject(path string, n Node) bool
+	// LeaveObject is called after the members of an Object node, unless they were skipped.
+	LeaveObject(path string, n Node)
+	// EnterArray is called for an Array node before its values, which are skipped if it returns false.
+	EnterArray(path string, n Node) bool
+	// LeaveArray is called after the values of an Array node, unless they were skipped.
+	LeaveArray(path string, n Node)
+	// Scalar is called for every other node, whose kind and value are those of n. Any, marshaler
+	// and Custom nodes are scalars too, since their content is only known once they are written.
+	Scalar(path string, n Node)
+}
+
+// Walk calls the methods of v for root and its descendants, depth first, in document order.
+func Walk(root Node, v Visitor) {
+	walk(root, "", v)
+}
+
+func walk(n Node, path string, v Visitor) {
+	switch n.Kind {
+	case Object:
+		if !v.EnterObject(path, n) {
+			return
+		}
+		for _, child := range n.Children {
+			walk(child, path+"/"+escape(child.Name), v)
+		}
+		v.LeaveObject(path, n)
+	case Array:
+		if !v.EnterArray(path, n) {
+			return
+		}
+		for i, child := range n.Children {
+			walk(child, path+"/"+strconv.Itoa(i), v)
+		}
+		v.LeaveArray(path, n)
+	default:
+		v.Scalar(path, n)
+	}
+}
+
+var escaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escape returns a member name as a reference token of a JSON Pointer.
+func escape(name string) string {
+	if !strings.ContainsAny(name, "~/") {
+		return name
+	}
+	return escaper.Replace(name)
+}
@@ -0,0 +1,63 @@
+package jsondf
+
+import "github.com/binadel/jsonw/jsond"
+
+// describer is implemented by the fields and values of the package, which describe themselves.
+type describer interface {
+	describe() jsond.Node
+}
+
+// Describe returns the description of the field and its subtree.
+// Nodes built by Each, MapOf, Frozen and Slot are described as Custom nodes, like the fields of
+// Custom, and so are a FieldFunc and Field implementations from outside the package, with an
+// empty name.
+func Describe(field Field) jsond.Node {
+	if d, ok := field.(describer); ok {
+		return d.describe()
+	}
+	return jsond.Node{Kind: jsond.Custom, Value: field.WriteField}
+}
+
+// DescribeItem returns the description of the value and its subtree, like Describe.
+func DescribeItem(value Value) jsond.Node {
+	if d, ok := value.(describer); ok {
+		return d.describe()
+	}
+	return jsond.Node{Kind: jsond.Custom, Value: value.WriteValue}
+}
+
+// Describe returns the description of the RootObject, an Object node.
+func (r RootObject) Describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Children: members(r)}
+}
+
+// Walk walks the description of the RootObject with v, without writing it.
+func (r RootObject) Walk(v jsond.Visitor) {
+	jsond.Walk(r.Describe(), v)
+}
+
+// Describe returns the description of the RootArray, an Array node.
+func (r RootArray) Describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Children: items(r)}
+}
+
+// Walk walks the description of the RootArray with v, without writing it.
+func (r RootArray) Walk(v jsond.Visitor) {
+	jsond.Walk(r.Describe(), v)
+}
+
+func members(fields []Field) []jsond.Node {
+	children := make([]jsond.Node, len(fields))
+	for i, field := range fields {
+		children[i] = Describe(field)
+	}
+	return children
+}
+
+func items(values []Value) []jsond.Node {
+	children := make([]jsond.Node, len(values))
+	for i, value := range values {
+		children[i] = DescribeItem(value)
+	}
+	return children
+}
@@ -0,0 +1,52 @@
+package jsondf
+
+import (
+	"github.com/binadel/jsonw/internal/sorted"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// Each creates an array field with a value for each item, built by fn when the field is written.
+func Each[T any](name string, items []T, fn func(item T) Value) Field {
+	return Custom(name, func(writer *jsoni.ObjectWriter) {
+		writer.Array(name, func(arr *jsoni.ArrayWriter) {
+			for _, item := range items {
+				fn(item).WriteValue(arr)
+			}
+		})
+	})
+}
+
+// EachItem creates an array value with a value for each item, built by fn when the value is written.
+func EachItem[T any](items []T, fn func(item T) Value) Value {
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		writer.Array(func(arr *jsoni.ArrayWriter) {
+			for _, item := range items {
+				fn(item).WriteValue(arr)
+			}
+		})
+	})
+}
+
+// MapOf creates a nested object field with a member for each entry of m, in sorted key order,
+// whose value is built by fn when the field is written. Keys are escaped.
+func MapOf[T any](name string, m map[string]T, fn func(value T) Value) Field {
+	return Custom(name, func(writer *jsoni.ObjectWriter) {
+		writer.Object(name, func(obj *jsoni.ObjectWriter) {
+			for _, key := range sorted.Keys(m) {
+				obj.Member(key, fn(m[key]).WriteValue)
+			}
+		})
+	})
+}
+
+// MapOfItem creates an object value with a member for each entry of m, in sorted key order,
+// whose value is built by fn when the value is written. Keys are escaped.
+func MapOfItem[T any](m map[string]T, fn func(value T) Value) Value {
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		writer.Object(func(obj *jsoni.ObjectWriter) {
+			for _, key := range sorted.Keys(m) {
+				obj.Member(key, fn(m[key]).WriteValue)
+			}
+		})
+	})
+}
@@ -0,0 +1,57 @@
+//go:build go1.23
+
+package jsondf
+
+import (
+	"iter"
+
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// EachSeq creates an array field with a value for each element of seq,
+// built by fn when the field is written. The sequence is iterated on every write.
+func EachSeq[T any](name string, seq iter.Seq[T], fn func(item T) Value) Field {
+	return Custom(name, func(writer *jsoni.ObjectWriter) {
+		writer.Array(name, func(arr *jsoni.ArrayWriter) {
+			for item := range seq {
+				fn(item).WriteValue(arr)
+			}
+		})
+	})
+}
+
+// EachSeqItem creates an array value with a value for each element of seq,
+// built by fn when the value is written. The sequence is iterated on every write.
+func EachSeqItem[T any](seq iter.Seq[T], fn func(item T) Value) Value {
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		writer.Array(func(arr *jsoni.ArrayWriter) {
+			for item := range seq {
+				fn(item).WriteValue(arr)
+			}
+		})
+	})
+}
+
+// MapOfSeq creates a nested object field with a member for each pair of seq, in the order of
+// the sequence, whose value is built by fn when the field is written. Keys are escaped.
+func MapOfSeq[T any](name string, seq iter.Seq2[string, T], fn func(value T) Value) Field {
+	return Custom(name, func(writer *jsoni.ObjectWriter) {
+		writer.Object(name, func(obj *jsoni.ObjectWriter) {
+			for key, value := range seq {
+				obj.Member(key, fn(value).WriteValue)
+			}
+		})
+	})
+}
+
+// MapOfSeqItem creates an object value with a member for each pair of seq, in the order of
+// the sequence, whose value is built by fn when the value is written. Keys are escaped.
+func MapOfSeqItem[T any](seq iter.Seq2[string, T], fn func(value T) Value) Value {
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		writer.Object(func(obj *jsoni.ObjectWriter) {
+			for key, value := range seq {
+				obj.Member(key, fn(value).WriteValue)
+			}
+		})
+	})
+}
@@ -1,76 +1,230 @@
 package jsondf
 
-import "github.com/binadel/jsonw/jsoni"
+import (
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
 
 // Object creates a nested object field.
 func Object(name string, fields ...Field) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		obj := writer.ObjectField(name)
-		obj.Open()
-		for _, field := range fields {
-			field(&obj)
-		}
-		obj.Close()
+	return objectField{name, fields}
+}
+
+type objectField struct {
+	name   string
+	fields []Field
+}
+
+func (f objectField) WriteField(writer *jsoni.ObjectWriter) {
+	obj := writer.ObjectField(f.name)
+	obj.Open()
+	for _, field := range f.fields {
+		field.WriteField(&obj)
 	}
+	obj.Close()
+}
+
+func (f objectField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Name: f.name, Children: members(f.fields)}
 }
 
 // Array creates a nested array field.
 func Array(name string, values ...Value) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		arr := writer.ArrayField(name)
-		arr.Open()
-		for _, value := range values {
-			value(&arr)
-		}
-		arr.Close()
+	return arrayField{name, values}
+}
+
+type arrayField struct {
+	name   string
+	values []Value
+}
+
+func (f arrayField) WriteField(writer *jsoni.ObjectWriter) {
+	arr := writer.ArrayField(f.name)
+	arr.Open()
+	for _, value := range f.values {
+		value.WriteValue(&arr)
 	}
+	arr.Close()
+}
+
+func (f arrayField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Name: f.name, Children: items(f.values)}
 }
 
 // String creates a string field.
 func String(name, value string) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.StringField(name, value)
-	}
+	return stringField{name, value}
+}
+
+type stringField struct {
+	name, value string
+}
+
+func (f stringField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.StringField(f.name, f.value)
+}
+
+func (f stringField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.String, Name: f.name, Value: f.value}
 }
 
 // Number creates a number field.
 func Number(name string, value string) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.NumberField(name, value)
-	}
+	return numberField{name, value}
+}
+
+type numberField struct {
+	name, value string
+}
+
+func (f numberField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.NumberField(f.name, f.value)
+}
+
+func (f numberField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Number, Name: f.name, Value: f.value}
 }
 
 // Integer creates an integer field.
 func Integer(name string, value int64) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.IntegerField(name, value)
-	}
+	return integerField{name, value}
+}
+
+type integerField struct {
+	name  string
+	value int64
+}
+
+func (f integerField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.IntegerField(f.name, f.value)
+}
+
+func (f integerField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Integer, Name: f.name, Value: f.value}
 }
 
 // Float creates a float field.
 func Float(name string, value float64) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.FloatField(name, value)
-	}
+	return floatField{name, value}
+}
+
+type floatField struct {
+	name  string
+	value float64
+}
+
+func (f floatField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.FloatField(f.name, f.value)
+}
+
+func (f floatField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Float, Name: f.name, Value: f.value}
 }
 
 // Boolean creates a boolean field.
 func Boolean(name string, value bool) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.BooleanField(name, value)
-	}
+	return booleanField{name, value}
+}
+
+type booleanField struct {
+	name  string
+	value bool
+}
+
+func (f booleanField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.BooleanField(f.name, f.value)
+}
+
+func (f booleanField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Boolean, Name: f.name, Value: f.value}
 }
 
 // Null creates a null field.
 func Null(name string) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.NullField(name)
-	}
+	return nullField{name}
+}
+
+type nullField struct {
+	name string
+}
+
+func (f nullField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.NullField(f.name)
+}
+
+func (f nullField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Null, Name: f.name}
 }
 
 // Any creates a dynamic field. Do not use it.
 func Any(name string, value any) Field {
-	return func(writer *jsoni.ObjectWriter) {
-		writer.AnyField(name, value)
-	}
+	return anyField{name, value}
+}
+
+type anyField struct {
+	name  string
+	value any
+}
+
+func (f anyField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.AnyField(f.name, f.value)
+}
+
+func (f anyField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Any, Name: f.name, Value: f.value}
+}
+
+// ObjectOf creates a nested object field written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOf(name string, value jsoni.ObjectMarshaler) Field {
+	return objectOfField{name, value}
+}
+
+type objectOfField struct {
+	name  string
+	value jsoni.ObjectMarshaler
+}
+
+func (f objectOfField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.AnyField(f.name, f.value)
+}
+
+func (f objectOfField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.ObjectOf, Name: f.name, Value: f.value}
+}
+
+// ValueOf creates a field written by a type implementing jsoni.ValueMarshaler.
+func ValueOf(name string, value jsoni.ValueMarshaler) Field {
+	return valueOfField{name, value}
+}
+
+type valueOfField struct {
+	name  string
+	value jsoni.ValueMarshaler
+}
+
+func (f valueOfField) WriteField(writer *jsoni.ObjectWriter) {
+	writer.AnyField(f.name, f.value)
+}
+
+func (f valueOfField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.ValueOf, Name: f.name, Value: f.value}
+}
+
+// Custom creates a user-defined field written by fn, which receives the parent object writer
+// and should write a single member named name.
+func Custom(name string, fn func(writer *jsoni.ObjectWriter)) Field {
+	return customField{name, fn}
+}
+
+type customField struct {
+	name string
+	fn   func(writer *jsoni.ObjectWriter)
+}
+
+func (f customField) WriteField(writer *jsoni.ObjectWriter) {
+	f.fn(writer)
+}
+
+func (f customField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.fn}
 }
@@ -0,0 +1,39 @@
+package jsondf
+
+import (
+	"github.com/binadel/jsonw/internal/frozen"
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// Frozen wraps a field whose subtree never changes, so that it is encoded once, by the first build
+// writing it, and the cached bytes are spliced into later builds. The cache is safe for concurrent use.
+// A subtree that fails to encode is not cached and is written normally, reporting its error.
+func Frozen(field Field) Field {
+	return frozenField{frozen.Fields(field.WriteField), field}
+}
+
+type frozenField struct {
+	cache *frozen.Cache
+	field Field
+}
+
+func (f frozenField) WriteField(writer *jsoni.ObjectWriter) {
+	if !f.cache.WriteFields(writer) {
+		f.field.WriteField(writer)
+	}
+}
+
+func (f frozenField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: Describe(f.field).Name, Value: f.WriteField}
+}
+
+// FrozenItem wraps a value whose subtree never changes, like Frozen.
+func FrozenItem(value Value) Value {
+	f := frozen.Value(value.WriteValue)
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		if !f.WriteValue(writer) {
+			value.WriteValue(writer)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package jsondf
+
+import "github.com/binadel/jsonw/jsond"
+
+// Merge returns a new root object made of base with the JSON Merge Patch (RFC 7396) patch applied,
+// without modifying either: the Null members of patch remove the members of the same name, its objects
+// are merged recursively, and its other members replace those of base, or are appended in the order
+// of patch. The members of base keep their order. Nodes whose content is only known once written,
+// like those of Custom, Each or Frozen, are replaced as a whole. See jsond.Merge.
+func Merge(base, patch RootObject) RootObject {
+	return API.RootObject(jsond.Merge(base.Describe(), patch.Describe()))
+}
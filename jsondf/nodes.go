@@ -3,7 +3,33 @@ package jsondf
 import "github.com/binadel/jsonw/jsoni"
 
 // Field represents an object field.
-type Field func(writer *jsoni.ObjectWriter)
+type Field interface {
+	// WriteField writes the field to an open object writer,
+	// which embeds it in a document written with jsoni.
+	WriteField(writer *jsoni.ObjectWriter)
+}
 
 // Value represents an array value.
-type Value func(writer *jsoni.ArrayWriter)
+type Value interface {
+	// WriteValue writes the value to an array writer,
+	// which embeds it in a document written with jsoni.
+	WriteValue(writer *jsoni.ArrayWriter)
+}
+
+// FieldFunc is a function writing a member, used as a Field.
+// It is described as a Custom node with an empty name.
+type FieldFunc func(writer *jsoni.ObjectWriter)
+
+// WriteField calls f.
+func (f FieldFunc) WriteField(writer *jsoni.ObjectWriter) {
+	f(writer)
+}
+
+// ValueFunc is a function writing a value, used as a Value.
+// It is described as a Custom node.
+type ValueFunc func(writer *jsoni.ArrayWriter)
+
+// WriteValue calls v.
+func (v ValueFunc) WriteValue(writer *jsoni.ArrayWriter) {
+	v(writer)
+}
@@ -0,0 +1,348 @@
+package jsondf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+var (
+	// ErrUnboundSlot is reported for a slot written without a bound value,
+	// either by a template execution or by a tree built directly.
+	ErrUnboundSlot = errors.New("jsondf: slot is not bound")
+	// ErrUnknownSlot is reported for a value bound to a name the template has no slot for.
+	ErrUnknownSlot = errors.New("jsondf: unknown slot")
+	// ErrSlotType is reported for a value whose type doesn't match its slot.
+	ErrSlotType = errors.New("jsondf: value type doesn't match the slot")
+)
+
+// SlotValue is the set of scalar types a slot can hold. A slot holding a whole subtree,
+// such as an array whose length changes with every execution, is made by ValueSlot.
+type SlotValue interface {
+	string | int64 | float64 | bool
+}
+
+type slotKind byte
+
+const (
+	slotString  slotKind = 's'
+	slotInteger slotKind = 'i'
+	slotFloat   slotKind = 'f'
+	slotBoolean slotKind = 'b'
+	slotValue   slotKind = 'v'
+)
+
+func (k slotKind) String() string {
+	switch k {
+	case slotString:
+		return "string"
+	case slotInteger:
+		return "int64"
+	case slotFloat:
+		return "float64"
+	case slotBoolean:
+		return "bool"
+	}
+	return "Value"
+}
+
+func kindOf[T SlotValue]() slotKind {
+	var zero T
+	switch any(zero).(type) {
+	case string:
+		return slotString
+	case int64:
+		return slotInteger
+	case float64:
+		return slotFloat
+	}
+	return slotBoolean
+}
+
+// compilations are the templates being compiled, keyed by the writer Compile writes them with.
+// The slots written there report their position through it, instead of writing anything.
+var compilations sync.Map // map[*jwriter.Writer]*compilation
+
+// compilation records the holes of a template being compiled, in the order they are written.
+type compilation struct {
+	holes   []hole
+	offsets []int // the position of each hole in the output
+}
+
+// hole is the value written in place of a slot.
+type hole struct {
+	name string
+	kind slotKind
+}
+
+// MarshalEasyJSON records the position of the slot when written by Compile, and fails otherwise.
+func (h hole) MarshalEasyJSON(w *jwriter.Writer) {
+	c, ok := compilations.Load(w)
+	if !ok {
+		w.Error = fmt.Errorf("%w: %s", ErrUnboundSlot, h.name)
+		return
+	}
+	record := c.(*compilation)
+	record.holes = append(record.holes, h)
+	record.offsets = append(record.offsets, w.Size())
+}
+
+// Slot creates a field whose value of type T is bound when a compiled template is executed.
+// Slots with the same name share their value. Building a tree with a slot directly fails with ErrUnboundSlot.
+func Slot[T SlotValue](name string) Field {
+	return newSlot(name, kindOf[T]())
+}
+
+// SlotItem creates an array value of type T, bound to the slot name when a compiled template is executed.
+func SlotItem[T SlotValue](name string) Value {
+	return newSlotItem(name, kindOf[T]())
+}
+
+// ValueSlot creates a field whose whole subtree is bound with BindValue, like Slot.
+func ValueSlot(name string) Field {
+	return newSlot(name, slotValue)
+}
+
+// ValueSlotItem creates an array value bound with BindValue, like SlotItem.
+func ValueSlotItem(name string) Value {
+	return newSlotItem(name, slotValue)
+}
+
+func newSlot(name string, kind slotKind) Field {
+	h := hole{name, kind}
+	return Custom(name, func(writer *jsoni.ObjectWriter) {
+		writer.AnyField(name, h)
+	})
+}
+
+func newSlotItem(name string, kind slotKind) Value {
+	h := hole{name, kind}
+	return ValueFunc(func(writer *jsoni.ArrayWriter) {
+		writer.AnyValue(h)
+	})
+}
+
+// Template is a compiled document, made of static byte runs written as they are
+// and typed holes filled with the values of a Binding.
+// A Template is immutable and safe for concurrent use.
+type Template struct {
+	static [][]byte // one more run than there are holes
+	holes  []int    // the index in slots of each hole
+	slots  []hole   // distinct slots, by name
+	index  map[string]int
+}
+
+// Compile writes the root object once, recording every static byte and the position of its slots.
+// Nodes other than slots are written at compile time, so their values are fixed in the template.
+func Compile(root RootObject) (*Template, error) {
+	return compile(func(w *jwriter.Writer) {
+		writer := jsoni.NewObjectWriter(w)
+		writer.Open()
+		root.WriteFields(&writer)
+		writer.Close()
+	})
+}
+
+// CompileArray writes the root array once, like Compile.
+func CompileArray(root RootArray) (*Template, error) {
+	return compile(func(w *jwriter.Writer) {
+		writer := jsoni.NewArrayWriter(w)
+		writer.Open()
+		root.WriteValues(&writer)
+		writer.Close()
+	})
+}
+
+// compile splits the output of write into static runs and the holes recorded in between.
+func compile(write func(w *jwriter.Writer)) (*Template, error) {
+	w := &jwriter.Writer{}
+	record := &compilation{}
+	compilations.Store(w, record)
+	defer compilations.Delete(w)
+
+	write(w)
+	data, err := w.BuildBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Template{index: map[string]int{}}
+	start := 0
+	for n, h := range record.holes {
+		offset := record.offsets[n]
+		if offset < start || offset > len(data) {
+			// Only a writer rolling back its output after a slot could leave it out of place.
+			return nil, fmt.Errorf("jsondf: slot %s was discarded from the output", h.name)
+		}
+
+		i, ok := t.index[h.name]
+		if !ok {
+			i = len(t.slots)
+			t.index[h.name] = i
+			t.slots = append(t.slots, h)
+		} else if t.slots[i].kind != h.kind {
+			return nil, fmt.Errorf("jsondf: slot %s is declared as both %s and %s", h.name, t.slots[i].kind, h.kind)
+		}
+
+		t.static = append(t.static, data[start:offset])
+		t.holes = append(t.holes, i)
+		start = offset
+	}
+	t.static = append(t.static, data[start:])
+	return t, nil
+}
+
+// Slots returns the names of the slots of the template, in the order they first appear.
+func (t *Template) Slots() []string {
+	names := make([]string, len(t.slots))
+	for i, s := range t.slots {
+		names[i] = s.name
+	}
+	return names
+}
+
+// bound is the value bound to a slot.
+type bound struct {
+	set bool
+	s   string
+	i   int64
+	f   float64
+	b   bool
+	v   Value
+}
+
+// Binding holds the values bound to the slots of a template for one execution.
+// It can be reused by calling Reset. A Binding is not safe for concurrent use.
+type Binding struct {
+	template *Template
+	values   []bound
+	err      error
+	writer   jsoni.ArrayWriter // writes the slots of type Value
+}
+
+// Bind returns a new binding of the template, with no values bound.
+func (t *Template) Bind() *Binding {
+	return &Binding{template: t, values: make([]bound, len(t.slots))}
+}
+
+// Reset unbinds all values and clears the recorded error, to reuse the binding.
+func (b *Binding) Reset() {
+	clear(b.values)
+	b.err = nil
+}
+
+// Bind binds value to the slot name of the binding. Binding a name the template doesn't have
+// or a value of the wrong type records an error, returned by Execute.
+func Bind[T SlotValue](b *Binding, name string, value T) {
+	slot := b.slot(name, kindOf[T]())
+	if slot == nil {
+		return
+	}
+
+	// Switching on a pointer keeps the value from being boxed.
+	switch v := any(&value).(type) {
+	case *string:
+		slot.s = *v
+	case *int64:
+		slot.i = *v
+	case *float64:
+		slot.f = *v
+	case *bool:
+		slot.b = *v
+	}
+	slot.set = true
+}
+
+// BindValue binds the subtree value to the slot name of the binding, made by ValueSlot or
+// ValueSlotItem. Errors are recorded like with Bind.
+func BindValue(b *Binding, name string, value Value) {
+	if slot := b.slot(name, slotValue); slot != nil {
+		slot.v = value
+		slot.set = true
+	}
+}
+
+// slot returns the value bound to the slot name, which must be of the given kind,
+// or records an error and returns nil.
+func (b *Binding) slot(name string, kind slotKind) *bound {
+	i, ok := b.template.index[name]
+	if !ok {
+		b.fail(fmt.Errorf("%w: %s", ErrUnknownSlot, name))
+		return nil
+	}
+	if kind != b.template.slots[i].kind {
+		b.fail(fmt.Errorf("%w: %s is %s, not %s", ErrSlotType, name, b.template.slots[i].kind, kind))
+		return nil
+	}
+	return &b.values[i]
+}
+
+func (b *Binding) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Execute writes the template with the bound values into JSON bytes.
+func (b *Binding) Execute() ([]byte, error) {
+	w := jwriter.Writer{}
+	if err := b.ExecuteInto(&w); err != nil {
+		return nil, err
+	}
+	return w.BuildBytes()
+}
+
+// MarshalEasyJSON writes the template with the bound values, so that a binding can be written
+// as a value of another document, for example with AnyItem or by a slot of type Value.
+func (b *Binding) MarshalEasyJSON(w *jwriter.Writer) {
+	if err := b.ExecuteInto(w); err != nil && w.Error == nil {
+		w.Error = err
+	}
+}
+
+// ExecuteInto writes the template with the bound values to an existing writer, such as a pooled one,
+// and returns the first error recorded while binding or writing.
+func (b *Binding) ExecuteInto(w *jwriter.Writer) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	t := b.template
+	for i, run := range t.static {
+		w.Buffer.AppendBytes(run)
+		if i == len(t.holes) {
+			break
+		}
+
+		slot := t.slots[t.holes[i]]
+		value := &b.values[t.holes[i]]
+		if !value.set || slot.kind == slotValue && value.v == nil {
+			return fmt.Errorf("%w: %s", ErrUnboundSlot, slot.name)
+		}
+		switch slot.kind {
+		case slotString:
+			w.String(value.s)
+		case slotInteger:
+			w.Int64(value.i)
+		case slotFloat:
+			if math.IsNaN(value.f) || math.IsInf(value.f, 0) {
+				return fmt.Errorf("jsondf: slot %s: %w", slot.name, jsoni.ErrUnsupportedFloat)
+			}
+			w.Float64(value.f)
+		case slotBoolean:
+			w.Bool(value.b)
+		case slotValue:
+			// An array writer that is never opened writes a single value in place.
+			b.writer = jsoni.NewArrayWriter(w)
+			value.v.WriteValue(&b.writer)
+		}
+		if w.Error != nil {
+			return w.Error
+		}
+	}
+	return nil
+}
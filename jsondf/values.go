@@ -1,76 +1,219 @@
 package jsondf
 
-import "github.com/binadel/jsonw/jsoni"
+import (
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
 
 // ObjectItem creates a nested object value.
 func ObjectItem(fields ...Field) Value {
-	return func(w *jsoni.ArrayWriter) {
-		obj := w.ObjectValue()
-		obj.Open()
-		for _, field := range fields {
-			field(&obj)
-		}
-		obj.Close()
+	return objectItem{fields}
+}
+
+type objectItem struct {
+	fields []Field
+}
+
+func (v objectItem) WriteValue(w *jsoni.ArrayWriter) {
+	obj := w.ObjectValue()
+	obj.Open()
+	for _, field := range v.fields {
+		field.WriteField(&obj)
 	}
+	obj.Close()
+}
+
+func (v objectItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Children: members(v.fields)}
 }
 
 // ArrayItem creates a nested array value.
 func ArrayItem(values ...Value) Value {
-	return func(w *jsoni.ArrayWriter) {
-		arr := w.ArrayValue()
-		arr.Open()
-		for _, value := range values {
-			value(&arr)
-		}
-		arr.Close()
+	return arrayItem{values}
+}
+
+type arrayItem struct {
+	values []Value
+}
+
+func (v arrayItem) WriteValue(w *jsoni.ArrayWriter) {
+	arr := w.ArrayValue()
+	arr.Open()
+	for _, value := range v.values {
+		value.WriteValue(&arr)
 	}
+	arr.Close()
+}
+
+func (v arrayItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Children: items(v.values)}
 }
 
 // StringItem creates a string value.
 func StringItem(value string) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.StringValue(value)
-	}
+	return stringItem{value}
+}
+
+type stringItem struct {
+	value string
+}
+
+func (v stringItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.StringValue(v.value)
+}
+
+func (v stringItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.String, Value: v.value}
 }
 
 // NumberItem creates a number value.
 func NumberItem(value string) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.NumberValue(value)
-	}
+	return numberItem{value}
+}
+
+type numberItem struct {
+	value string
+}
+
+func (v numberItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.NumberValue(v.value)
+}
+
+func (v numberItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Number, Value: v.value}
 }
 
 // IntegerItem creates an integer value.
 func IntegerItem(value int64) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.IntegerValue(value)
-	}
+	return integerItem{value}
+}
+
+type integerItem struct {
+	value int64
+}
+
+func (v integerItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.IntegerValue(v.value)
+}
+
+func (v integerItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Integer, Value: v.value}
 }
 
 // FloatItem creates a float value.
 func FloatItem(value float64) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.FloatValue(value)
-	}
+	return floatItem{value}
+}
+
+type floatItem struct {
+	value float64
+}
+
+func (v floatItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.FloatValue(v.value)
+}
+
+func (v floatItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Float, Value: v.value}
 }
 
 // BooleanItem creates a boolean value.
 func BooleanItem(value bool) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.BooleanValue(value)
-	}
+	return booleanItem{value}
+}
+
+type booleanItem struct {
+	value bool
+}
+
+func (v booleanItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.BooleanValue(v.value)
+}
+
+func (v booleanItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Boolean, Value: v.value}
 }
 
 // NullItem creates a null value.
 func NullItem() Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.NullValue()
-	}
+	return nullItem{}
+}
+
+type nullItem struct{}
+
+func (v nullItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.NullValue()
+}
+
+func (v nullItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Null}
 }
 
 // AnyItem creates a dynamic value. Do not use it.
 func AnyItem(value any) Value {
-	return func(w *jsoni.ArrayWriter) {
-		w.AnyValue(value)
-	}
+	return anyItem{value}
+}
+
+type anyItem struct {
+	value any
+}
+
+func (v anyItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.AnyValue(v.value)
+}
+
+func (v anyItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Any, Value: v.value}
+}
+
+// ObjectOfItem creates a nested object value written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOfItem(value jsoni.ObjectMarshaler) Value {
+	return objectOfItem{value}
+}
+
+type objectOfItem struct {
+	value jsoni.ObjectMarshaler
+}
+
+func (v objectOfItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.AnyValue(v.value)
+}
+
+func (v objectOfItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.ObjectOf, Value: v.value}
+}
+
+// ValueOfItem creates a value written by a type implementing jsoni.ValueMarshaler.
+func ValueOfItem(value jsoni.ValueMarshaler) Value {
+	return valueOfItem{value}
+}
+
+type valueOfItem struct {
+	value jsoni.ValueMarshaler
+}
+
+func (v valueOfItem) WriteValue(w *jsoni.ArrayWriter) {
+	w.AnyValue(v.value)
+}
+
+func (v valueOfItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.ValueOf, Value: v.value}
+}
+
+// CustomItem creates a user-defined value written by fn, which receives the parent array writer
+// and should write a single value.
+func CustomItem(fn func(writer *jsoni.ArrayWriter)) Value {
+	return customItem{fn}
+}
+
+type customItem struct {
+	fn func(writer *jsoni.ArrayWriter)
+}
+
+func (v customItem) WriteValue(w *jsoni.ArrayWriter) {
+	v.fn(w)
+}
+
+func (v customItem) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.fn}
 }
@@ -0,0 +1,99 @@
+package jsondi
+
+import (
+	"github.com/binadel/jsonw/internal/sorted"
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+type eachField[T any] struct {
+	name  string
+	items []T
+	fn    func(item T) Value
+}
+
+// Each creates an array field with a value for each item, built by fn when the field is written.
+func Each[T any](name string, items []T, fn func(item T) Value) Field {
+	return eachField[T]{name, items, fn}
+}
+
+func (f eachField[T]) WriteField(writer *jsoni.ObjectWriter) {
+	writer.Array(f.name, func(arr *jsoni.ArrayWriter) {
+		for _, item := range f.items {
+			f.fn(item).WriteValue(arr)
+		}
+	})
+}
+
+func (f eachField[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.WriteField}
+}
+
+type eachValue[T any] struct {
+	items []T
+	fn    func(item T) Value
+}
+
+// EachItem creates an array value with a value for each item, built by fn when the value is written.
+func EachItem[T any](items []T, fn func(item T) Value) Value {
+	return eachValue[T]{items, fn}
+}
+
+func (v eachValue[T]) WriteValue(writer *jsoni.ArrayWriter) {
+	writer.Array(func(arr *jsoni.ArrayWriter) {
+		for _, item := range v.items {
+			v.fn(item).WriteValue(arr)
+		}
+	})
+}
+
+func (v eachValue[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.WriteValue}
+}
+
+type mapField[T any] struct {
+	name string
+	m    map[string]T
+	fn   func(value T) Value
+}
+
+// MapOf creates a nested object field with a member for each entry of m, in sorted key order,
+// whose value is built by fn when the field is written. Keys are escaped.
+func MapOf[T any](name string, m map[string]T, fn func(value T) Value) Field {
+	return mapField[T]{name, m, fn}
+}
+
+func (f mapField[T]) WriteField(writer *jsoni.ObjectWriter) {
+	writer.Object(f.name, func(obj *jsoni.ObjectWriter) {
+		for _, key := range sorted.Keys(f.m) {
+			obj.Member(key, f.fn(f.m[key]).WriteValue)
+		}
+	})
+}
+
+func (f mapField[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.WriteField}
+}
+
+type mapValue[T any] struct {
+	m  map[string]T
+	fn func(value T) Value
+}
+
+// MapOfItem creates an object value with a member for each entry of m, in sorted key order,
+// whose value is built by fn when the value is written. Keys are escaped.
+func MapOfItem[T any](m map[string]T, fn func(value T) Value) Value {
+	return mapValue[T]{m, fn}
+}
+
+func (v mapValue[T]) WriteValue(writer *jsoni.ArrayWriter) {
+	writer.Object(func(obj *jsoni.ObjectWriter) {
+		for _, key := range sorted.Keys(v.m) {
+			obj.Member(key, v.fn(v.m[key]).WriteValue)
+		}
+	})
+}
+
+func (v mapValue[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.WriteValue}
+}
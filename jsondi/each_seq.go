@@ -0,0 +1,104 @@
+//go:build go1.23
+
+package jsondi
+
+import (
+	"iter"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+type eachSeqField[T any] struct {
+	name string
+	seq  iter.Seq[T]
+	fn   func(item T) Value
+}
+
+// EachSeq creates an array field with a value for each element of seq,
+// built by fn when the field is written. The sequence is iterated on every write.
+func EachSeq[T any](name string, seq iter.Seq[T], fn func(item T) Value) Field {
+	return eachSeqField[T]{name, seq, fn}
+}
+
+func (f eachSeqField[T]) WriteField(writer *jsoni.ObjectWriter) {
+	writer.Array(f.name, func(arr *jsoni.ArrayWriter) {
+		for item := range f.seq {
+			f.fn(item).WriteValue(arr)
+		}
+	})
+}
+
+func (f eachSeqField[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.WriteField}
+}
+
+type eachSeqValue[T any] struct {
+	seq iter.Seq[T]
+	fn  func(item T) Value
+}
+
+// EachSeqItem creates an array value with a value for each element of seq,
+// built by fn when the value is written. The sequence is iterated on every write.
+func EachSeqItem[T any](seq iter.Seq[T], fn func(item T) Value) Value {
+	return eachSeqValue[T]{seq, fn}
+}
+
+func (v eachSeqValue[T]) WriteValue(writer *jsoni.ArrayWriter) {
+	writer.Array(func(arr *jsoni.ArrayWriter) {
+		for item := range v.seq {
+			v.fn(item).WriteValue(arr)
+		}
+	})
+}
+
+func (v eachSeqValue[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.WriteValue}
+}
+
+type mapSeqField[T any] struct {
+	name string
+	seq  iter.Seq2[string, T]
+	fn   func(value T) Value
+}
+
+// MapOfSeq creates a nested object field with a member for each pair of seq, in the order of
+// the sequence, whose value is built by fn when the field is written. Keys are escaped.
+func MapOfSeq[T any](name string, seq iter.Seq2[string, T], fn func(value T) Value) Field {
+	return mapSeqField[T]{name, seq, fn}
+}
+
+func (f mapSeqField[T]) WriteField(writer *jsoni.ObjectWriter) {
+	writer.Object(f.name, func(obj *jsoni.ObjectWriter) {
+		for key, value := range f.seq {
+			obj.Member(key, f.fn(value).WriteValue)
+		}
+	})
+}
+
+func (f mapSeqField[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.WriteField}
+}
+
+type mapSeqValue[T any] struct {
+	seq iter.Seq2[string, T]
+	fn  func(value T) Value
+}
+
+// MapOfSeqItem creates an object value with a member for each pair of seq, in the order of
+// the sequence, whose value is built by fn when the value is written. Keys are escaped.
+func MapOfSeqItem[T any](seq iter.Seq2[string, T], fn func(value T) Value) Value {
+	return mapSeqValue[T]{seq, fn}
+}
+
+func (v mapSeqValue[T]) WriteValue(writer *jsoni.ArrayWriter) {
+	writer.Object(func(obj *jsoni.ObjectWriter) {
+		for key, value := range v.seq {
+			obj.Member(key, v.fn(value).WriteValue)
+		}
+	})
+}
+
+func (v mapSeqValue[T]) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.WriteValue}
+}
@@ -1,6 +1,9 @@
 package jsondi
 
-import "github.com/binadel/jsonw/jsoni"
+import (
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
 
 type objectField struct {
 	name   string
@@ -12,15 +15,19 @@ func Object(name string, fields ...Field) Field {
 	return objectField{name, fields}
 }
 
-func (f objectField) write(writer *jsoni.ObjectWriter) {
+func (f objectField) WriteField(writer *jsoni.ObjectWriter) {
 	obj := writer.ObjectField(f.name)
 	obj.Open()
 	for _, field := range f.fields {
-		field.write(&obj)
+		field.WriteField(&obj)
 	}
 	obj.Close()
 }
 
+func (f objectField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Name: f.name, Children: members(f.fields)}
+}
+
 type arrayField struct {
 	name   string
 	values []Value
@@ -31,15 +38,19 @@ func Array(name string, values ...Value) Field {
 	return arrayField{name, values}
 }
 
-func (f arrayField) write(writer *jsoni.ObjectWriter) {
+func (f arrayField) WriteField(writer *jsoni.ObjectWriter) {
 	arr := writer.ArrayField(f.name)
 	arr.Open()
 	for _, value := range f.values {
-		value.write(&arr)
+		value.WriteValue(&arr)
 	}
 	arr.Close()
 }
 
+func (f arrayField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Name: f.name, Children: items(f.values)}
+}
+
 type stringField struct {
 	name, value string
 }
@@ -49,10 +60,14 @@ func String(name, value string) Field {
 	return stringField{name, value}
 }
 
-func (f stringField) write(writer *jsoni.ObjectWriter) {
+func (f stringField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.StringField(f.name, f.value)
 }
 
+func (f stringField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.String, Name: f.name, Value: f.value}
+}
+
 type numberField struct {
 	name, value string
 }
@@ -62,10 +77,14 @@ func Number(name, value string) Field {
 	return numberField{name, value}
 }
 
-func (f numberField) write(writer *jsoni.ObjectWriter) {
+func (f numberField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.NumberField(f.name, f.value)
 }
 
+func (f numberField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Number, Name: f.name, Value: f.value}
+}
+
 type integerField struct {
 	name  string
 	value int64
@@ -76,10 +95,14 @@ func Integer(name string, value int64) Field {
 	return integerField{name, value}
 }
 
-func (f integerField) write(writer *jsoni.ObjectWriter) {
+func (f integerField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.IntegerField(f.name, f.value)
 }
 
+func (f integerField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Integer, Name: f.name, Value: f.value}
+}
+
 type floatField struct {
 	name  string
 	value float64
@@ -90,10 +113,14 @@ func Float(name string, value float64) Field {
 	return floatField{name, value}
 }
 
-func (f floatField) write(writer *jsoni.ObjectWriter) {
+func (f floatField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.FloatField(f.name, f.value)
 }
 
+func (f floatField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Float, Name: f.name, Value: f.value}
+}
+
 type booleanField struct {
 	name  string
 	value bool
@@ -104,10 +131,14 @@ func Boolean(name string, value bool) Field {
 	return booleanField{name, value}
 }
 
-func (f booleanField) write(writer *jsoni.ObjectWriter) {
+func (f booleanField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.BooleanField(f.name, f.value)
 }
 
+func (f booleanField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Boolean, Name: f.name, Value: f.value}
+}
+
 type nullField struct {
 	name string
 }
@@ -117,20 +148,58 @@ func Null(name string) Field {
 	return nullField{name}
 }
 
-func (f nullField) write(writer *jsoni.ObjectWriter) {
+func (f nullField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.NullField(f.name)
 }
 
+func (f nullField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Null, Name: f.name}
+}
+
 type anyField struct {
 	name  string
 	value any
+	kind  jsond.Kind // Any, ObjectOf or ValueOf, as it is written the same way
 }
 
 // Any creates a dynamic field. Do not use it.
 func Any(name string, value any) Field {
-	return anyField{name, value}
+	return anyField{name, value, jsond.Any}
 }
 
-func (f anyField) write(writer *jsoni.ObjectWriter) {
+func (f anyField) WriteField(writer *jsoni.ObjectWriter) {
 	writer.AnyField(f.name, f.value)
 }
+
+func (f anyField) describe() jsond.Node {
+	return jsond.Node{Kind: f.kind, Name: f.name, Value: f.value}
+}
+
+// ObjectOf creates a nested object field written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOf(name string, value jsoni.ObjectMarshaler) Field {
+	return anyField{name, value, jsond.ObjectOf}
+}
+
+// ValueOf creates a field written by a type implementing jsoni.ValueMarshaler.
+func ValueOf(name string, value jsoni.ValueMarshaler) Field {
+	return anyField{name, value, jsond.ValueOf}
+}
+
+type customField struct {
+	name string
+	fn   func(writer *jsoni.ObjectWriter)
+}
+
+// Custom creates a user-defined field written by fn, which receives the parent object writer
+// and should write a single member named name.
+func Custom(name string, fn func(writer *jsoni.ObjectWriter)) Field {
+	return customField{name, fn}
+}
+
+func (f customField) WriteField(writer *jsoni.ObjectWriter) {
+	f.fn(writer)
+}
+
+func (f customField) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Name: f.name, Value: f.fn}
+}
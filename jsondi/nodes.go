@@ -4,10 +4,14 @@ import "github.com/binadel/jsonw/jsoni"
 
 // Field represents an object field.
 type Field interface {
-	write(writer *jsoni.ObjectWriter)
+	// WriteField writes the field to an open object writer,
+	// which embeds it in a document written with jsoni.
+	WriteField(writer *jsoni.ObjectWriter)
 }
 
 // Value represents an array value.
 type Value interface {
-	write(writer *jsoni.ArrayWriter)
+	// WriteValue writes the value to an array writer,
+	// which embeds it in a document written with jsoni.
+	WriteValue(writer *jsoni.ArrayWriter)
 }
@@ -6,6 +6,8 @@ import (
 )
 
 // RootObject represents a json root object.
+// Writing a tree doesn't modify it, so a built tree can be shared and written by several
+// goroutines at once, as long as its Any and marshaler values and custom functions allow it.
 type RootObject []Field
 
 // New creates a new root object.
@@ -18,14 +20,31 @@ func (r RootObject) Build() ([]byte, error) {
 	w := jwriter.Writer{}
 	writer := jsoni.NewObjectWriter(&w)
 	writer.Open()
+	r.WriteFields(&writer)
+	writer.Close()
+	return writer.BuildBytes()
+}
+
+// WriteFields writes the fields of the RootObject to an open object writer,
+// which embeds them in a document written with jsoni.
+func (r RootObject) WriteFields(writer *jsoni.ObjectWriter) {
 	for _, field := range r {
-		field.write(&writer)
+		field.WriteField(writer)
 	}
+}
+
+// BuildInto writes the RootObject to an existing writer, such as a pooled one,
+// leaving the output there, and returns the first error recorded while writing.
+func (r RootObject) BuildInto(w *jwriter.Writer) error {
+	writer := jsoni.NewObjectWriter(w)
+	writer.Open()
+	r.WriteFields(&writer)
 	writer.Close()
-	return writer.BuildBytes()
+	return writer.Err()
 }
 
 // RootArray represents a json root array.
+// Like a RootObject, it can be shared and written by several goroutines at once.
 type RootArray []Value
 
 // NewArray creates a new root array.
@@ -38,9 +57,25 @@ func (r RootArray) Build() ([]byte, error) {
 	w := jwriter.Writer{}
 	writer := jsoni.NewArrayWriter(&w)
 	writer.Open()
+	r.WriteValues(&writer)
+	writer.Close()
+	return writer.BuildBytes()
+}
+
+// WriteValues writes the values of the RootArray to an open array writer,
+// which embeds them in a document written with jsoni.
+func (r RootArray) WriteValues(writer *jsoni.ArrayWriter) {
 	for _, value := range r {
-		value.write(&writer)
+		value.WriteValue(writer)
 	}
+}
+
+// BuildInto writes the RootArray to an existing writer, such as a pooled one,
+// leaving the output there, and returns the first error recorded while writing.
+func (r RootArray) BuildInto(w *jwriter.Writer) error {
+	writer := jsoni.NewArrayWriter(w)
+	writer.Open()
+	r.WriteValues(&writer)
 	writer.Close()
-	return writer.BuildBytes()
+	return writer.Err()
 }
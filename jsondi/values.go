@@ -1,6 +1,9 @@
 package jsondi
 
-import "github.com/binadel/jsonw/jsoni"
+import (
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsoni"
+)
 
 type objectValue struct {
 	fields []Field
@@ -11,15 +14,19 @@ func ObjectItem(fields ...Field) Value {
 	return objectValue{fields}
 }
 
-func (v objectValue) write(writer *jsoni.ArrayWriter) {
+func (v objectValue) WriteValue(writer *jsoni.ArrayWriter) {
 	obj := writer.ObjectValue()
 	obj.Open()
 	for _, field := range v.fields {
-		field.write(&obj)
+		field.WriteField(&obj)
 	}
 	obj.Close()
 }
 
+func (v objectValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Children: members(v.fields)}
+}
+
 type arrayValue struct {
 	values []Value
 }
@@ -29,15 +36,19 @@ func ArrayItem(values ...Value) Value {
 	return arrayValue{values}
 }
 
-func (v arrayValue) write(writer *jsoni.ArrayWriter) {
+func (v arrayValue) WriteValue(writer *jsoni.ArrayWriter) {
 	arr := writer.ArrayValue()
 	arr.Open()
 	for _, value := range v.values {
-		value.write(&arr)
+		value.WriteValue(&arr)
 	}
 	arr.Close()
 }
 
+func (v arrayValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Array, Children: items(v.values)}
+}
+
 type stringValue struct {
 	value string
 }
@@ -47,10 +58,14 @@ func StringItem(value string) Value {
 	return stringValue{value}
 }
 
-func (v stringValue) write(writer *jsoni.ArrayWriter) {
+func (v stringValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.StringValue(v.value)
 }
 
+func (v stringValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.String, Value: v.value}
+}
+
 type numberValue struct {
 	value string
 }
@@ -60,10 +75,14 @@ func NumberItem(value string) Value {
 	return numberValue{value}
 }
 
-func (v numberValue) write(writer *jsoni.ArrayWriter) {
+func (v numberValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.NumberValue(v.value)
 }
 
+func (v numberValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Number, Value: v.value}
+}
+
 type integerValue struct {
 	value int64
 }
@@ -73,10 +92,14 @@ func IntegerItem(value int64) Value {
 	return integerValue{value}
 }
 
-func (v integerValue) write(writer *jsoni.ArrayWriter) {
+func (v integerValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.IntegerValue(v.value)
 }
 
+func (v integerValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Integer, Value: v.value}
+}
+
 type floatValue struct {
 	value float64
 }
@@ -86,10 +109,14 @@ func FloatItem(value float64) Value {
 	return floatValue{value}
 }
 
-func (v floatValue) write(writer *jsoni.ArrayWriter) {
+func (v floatValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.FloatValue(v.value)
 }
 
+func (v floatValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Float, Value: v.value}
+}
+
 type booleanValue struct {
 	value bool
 }
@@ -99,10 +126,14 @@ func BooleanItem(value bool) Value {
 	return booleanValue{value}
 }
 
-func (v booleanValue) write(writer *jsoni.ArrayWriter) {
+func (v booleanValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.BooleanValue(v.value)
 }
 
+func (v booleanValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Boolean, Value: v.value}
+}
+
 type nullValue struct{}
 
 // NullItem creates a null value.
@@ -110,19 +141,56 @@ func NullItem() Value {
 	return nullValue{}
 }
 
-func (v nullValue) write(writer *jsoni.ArrayWriter) {
+func (v nullValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.NullValue()
 }
 
+func (v nullValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Null}
+}
+
 type anyValue struct {
 	value any
+	kind  jsond.Kind // Any, ObjectOf or ValueOf, as it is written the same way
 }
 
 // AnyItem creates a dynamic value. Do not use it.
 func AnyItem(value any) Value {
-	return anyValue{value}
+	return anyValue{value, jsond.Any}
 }
 
-func (v anyValue) write(writer *jsoni.ArrayWriter) {
+func (v anyValue) WriteValue(writer *jsoni.ArrayWriter) {
 	writer.AnyValue(v.value)
 }
+
+func (v anyValue) describe() jsond.Node {
+	return jsond.Node{Kind: v.kind, Value: v.value}
+}
+
+// ObjectOfItem creates a nested object value written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOfItem(value jsoni.ObjectMarshaler) Value {
+	return anyValue{value, jsond.ObjectOf}
+}
+
+// ValueOfItem creates a value written by a type implementing jsoni.ValueMarshaler.
+func ValueOfItem(value jsoni.ValueMarshaler) Value {
+	return anyValue{value, jsond.ValueOf}
+}
+
+type customValue struct {
+	fn func(writer *jsoni.ArrayWriter)
+}
+
+// CustomItem creates a user-defined value written by fn, which receives the parent array writer
+// and should write a single value.
+func CustomItem(fn func(writer *jsoni.ArrayWriter)) Value {
+	return customValue{fn}
+}
+
+func (v customValue) WriteValue(writer *jsoni.ArrayWriter) {
+	v.fn(writer)
+}
+
+func (v customValue) describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Custom, Value: v.fn}
+}
@@ -0,0 +1,223 @@
+package jsonds
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/binadel/jsonw/internal/number"
+	"github.com/binadel/jsonw/internal/sorted"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// ErrUnsupportedType is reported by FromAny for a Go value with no JSON counterpart.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// FromAny builds a typed tree from the generic representation of JSON used by encoding/json and
+// other libraries: map[string]any, []any, string, bool, nil, float64 and json.Number, as well as
+// the other integer and float types. Object members are sorted by key, like encoding/json sorts
+// map keys, and keys are escaped, since member names are written as they are.
+//
+// The errors report the JSON Pointer of the offending value: a value of another type, a NaN or
+// infinite float, or an invalid json.Number. An object value can be made a root object with
+// RootObject.Set("", value).
+func FromAny(value any) (Value, error) {
+	return fromAny(value, "")
+}
+
+func fromAny(value any, path string) (Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return NullItem(), nil
+	case map[string]any:
+		keys := sorted.Keys(v)
+		children := make([]Field, len(keys))
+		for i, key := range keys {
+			child, err := fromAny(v[key], path+"/"+escapePointer(key))
+			if err != nil {
+				return Value{}, err
+			}
+			children[i] = Field{escapeName(key), child}
+		}
+		return Value{kind: kindObject, children: children}, nil
+	case []any:
+		children := make([]Field, len(v))
+		for i, item := range v {
+			child, err := fromAny(item, path+"/"+strconv.Itoa(i))
+			if err != nil {
+				return Value{}, err
+			}
+			children[i].value = child
+		}
+		return Value{kind: kindArray, children: children}, nil
+	case string:
+		return StringItem(v), nil
+	case bool:
+		return BooleanItem(v), nil
+	case json.Number:
+		if !number.Valid(string(v)) {
+			return Value{}, anyError(path, jsoni.ErrInvalidNumber)
+		}
+		return NumberItem(string(v)), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Value{}, anyError(path, jsoni.ErrUnsupportedFloat)
+		}
+		return FloatItem(v), nil
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return Value{}, anyError(path, jsoni.ErrUnsupportedFloat)
+		}
+		// Formatting with 32 bits keeps 0.1 from being written as 0.10000000149011612.
+		return NumberItem(strconv.FormatFloat(float64(v), 'g', -1, 32)), nil
+	case int:
+		return IntegerItem(int64(v)), nil
+	case int8:
+		return IntegerItem(int64(v)), nil
+	case int16:
+		return IntegerItem(int64(v)), nil
+	case int32:
+		return IntegerItem(int64(v)), nil
+	case int64:
+		return IntegerItem(v), nil
+	case uint:
+		return fromUint(uint64(v)), nil
+	case uint8:
+		return fromUint(uint64(v)), nil
+	case uint16:
+		return fromUint(uint64(v)), nil
+	case uint32:
+		return fromUint(uint64(v)), nil
+	case uint64:
+		return fromUint(v), nil
+	}
+	return Value{}, anyError(path, fmt.Errorf("%w %T", ErrUnsupportedType, value))
+}
+
+// fromUint returns an integer, or a number for values too large for an int64.
+func fromUint(v uint64) Value {
+	if v > math.MaxInt64 {
+		return NumberItem(strconv.FormatUint(v, 10))
+	}
+	return IntegerItem(int64(v))
+}
+
+func anyError(path string, err error) error {
+	if path == "" {
+		return fmt.Errorf("jsonds: %w", err)
+	}
+	return fmt.Errorf("jsonds: %s: %w", path, err)
+}
+
+// ToAny returns the generic representation of the RootObject, a map[string]any, for libraries
+// working with the types produced by encoding/json. Objects are map[string]any and arrays []any,
+// numbers are json.Number, and integers and floats are int64 and float64, which FromAny turns back
+// into the same nodes. Member names are unescaped, and of members with the same name the last wins.
+//
+// Any, marshaler and Custom nodes are written and decoded like encoding/json does with UseNumber.
+// A Custom member adds the members it writes, and the nodes failing to write are left out of
+// objects and are nil in arrays.
+func ToAny(root RootObject) any {
+	return objectToAny(root)
+}
+
+func objectToAny(fields []Field) map[string]any {
+	m := make(map[string]any, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		if !f.value.opaque() {
+			m[unescapeName(f.name)] = toAny(&f.value)
+			continue
+		}
+		// The content of the other nodes is only known once written.
+		var members map[string]any
+		if decode(RootObject{*f}.Build, &members) {
+			for name, member := range members {
+				m[name] = member
+			}
+		}
+	}
+	return m
+}
+
+func toAny(v *Value) any {
+	switch v.kind {
+	case kindObject:
+		return objectToAny(v.children)
+	case kindArray:
+		s := make([]any, len(v.children))
+		for i := range v.children {
+			s[i] = toAny(&v.children[i].value)
+		}
+		return s
+	case kindString:
+		return v.s
+	case kindNumber:
+		return json.Number(v.s)
+	case kindInteger:
+		return v.integer()
+	case kindFloat:
+		return v.float()
+	case kindBoolean:
+		return v.boolean()
+	case kindNull:
+		return nil
+	}
+
+	var values []any
+	if !decode(RootArray{*v}.Build, &values) || len(values) != 1 {
+		return nil
+	}
+	return values[0]
+}
+
+// opaque reports whether the content of the value is only known once written.
+func (v *Value) opaque() bool {
+	switch v.kind {
+	case kindAny, kindObjectOf, kindValueOf, kindCustom, kindCustomField:
+		return true
+	}
+	return false
+}
+
+// decode builds a document and decodes it into target, keeping numbers as json.Number.
+func decode(build func() ([]byte, error), target any) bool {
+	data, err := build()
+	if err != nil {
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(target) == nil
+}
+
+// escapeName returns a key escaped as a member name, which the writers write as it is.
+func escapeName(key string) string {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' || c >= 0x80 {
+			w := jwriter.Writer{}
+			w.String(key)
+			escaped := w.Buffer.BuildBytes()
+			return string(escaped[1 : len(escaped)-1])
+		}
+	}
+	return key
+}
+
+// unescapeName returns the key of an escaped member name, or the name itself if it isn't valid.
+func unescapeName(name string) string {
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' {
+			var key string
+			if err := json.Unmarshal([]byte(`"`+name+`"`), &key); err != nil {
+				return name
+			}
+			return key
+		}
+	}
+	return name
+}
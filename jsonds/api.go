@@ -0,0 +1,46 @@
+package jsonds
+
+import "github.com/binadel/jsonw/jsond"
+
+// API is the constructor set of jsonds, as described by the contract shared by the declarative packages.
+var API = jsond.API[Field, Value, RootObject, RootArray]{
+	Name: "jsonds",
+
+	New:      New,
+	NewArray: NewArray,
+
+	Object:   Object,
+	Array:    Array,
+	String:   String,
+	Number:   Number,
+	Integer:  Integer,
+	Float:    Float,
+	Boolean:  Boolean,
+	Null:     Null,
+	Any:      Any,
+	ObjectOf: ObjectOf,
+	ValueOf:  ValueOf,
+	Custom:   Custom,
+	Frozen:   Frozen,
+
+	ObjectItem:   ObjectItem,
+	ArrayItem:    ArrayItem,
+	StringItem:   StringItem,
+	NumberItem:   NumberItem,
+	IntegerItem:  IntegerItem,
+	FloatItem:    FloatItem,
+	BooleanItem:  BooleanItem,
+	NullItem:     NullItem,
+	AnyItem:      AnyItem,
+	ObjectOfItem: ObjectOfItem,
+	ValueOfItem:  ValueOfItem,
+	CustomItem:   CustomItem,
+	FrozenItem:   FrozenItem,
+
+	Describe:     Describe,
+	DescribeItem: DescribeItem,
+}
+
+func init() {
+	jsond.Register(API.Implementation())
+}
@@ -0,0 +1,114 @@
+package jsonds
+
+// Arena allocates the children of objects and arrays from reusable slabs, instead of copying
+// every variadic slice into fresh heap memory like Object and Array do. Once its slabs have grown
+// to the size of a document, building the same shape again after Reset doesn't allocate.
+//
+// The zero value is ready to use. Nodes built with an arena are only valid until it is reset,
+// and an arena is not safe for concurrent use, so a server would keep one per request,
+// for example in a sync.Pool.
+type Arena struct {
+	fields slab[Field]
+	values slab[Value]
+}
+
+// Reset makes the memory of the arena available again, invalidating every node built with it.
+func (a *Arena) Reset() {
+	a.fields.reset()
+	a.values.reset()
+}
+
+// Fields returns a slice of n fields from the arena, to be filled by the caller.
+func (a *Arena) Fields(n int) []Field {
+	return a.fields.alloc(n)
+}
+
+// Values returns a slice of n values from the arena, to be filled by the caller.
+func (a *Arena) Values(n int) []Value {
+	return a.values.alloc(n)
+}
+
+// New creates a new root object like New, with the fields copied into the arena.
+func (a *Arena) New(fields ...Field) RootObject {
+	return a.fields.copy(fields)
+}
+
+// NewArray creates a new root array like NewArray, with the values copied into the arena.
+func (a *Arena) NewArray(values ...Value) RootArray {
+	return a.values.copy(values)
+}
+
+// Object creates a nested object field like Object, with the fields copied into the arena.
+func (a *Arena) Object(name string, fields ...Field) Field {
+	return Field{name, a.ObjectItem(fields...)}
+}
+
+// Array creates a nested array field like Array, with the values copied into the arena.
+func (a *Arena) Array(name string, values ...Value) Field {
+	return Field{name, a.ArrayItem(values...)}
+}
+
+// ObjectItem creates an object value like ObjectItem, with the fields copied into the arena.
+func (a *Arena) ObjectItem(fields ...Field) Value {
+	return Value{kind: kindObject, children: a.fields.copy(fields)}
+}
+
+// ArrayItem creates an array value like ArrayItem, with the values copied into the arena.
+func (a *Arena) ArrayItem(values ...Value) Value {
+	return Value{kind: kindArray, children: items(a.fields.alloc(len(values)), values)}
+}
+
+// minSlab is the size of the first slab of an arena.
+const minSlab = 64
+
+// slab hands out slices of chunks that are kept across resets.
+// A full chunk is never grown, since the slices handed out point into it;
+// a larger one is added after it instead.
+type slab[T any] struct {
+	chunks [][]T
+	chunk  int // index of the chunk being filled
+	used   int // number of elements used in that chunk
+}
+
+// alloc returns a zeroed slice of n elements, whose capacity is capped
+// so that appending to it can't overwrite the next allocation.
+func (s *slab[T]) alloc(n int) []T {
+	for s.chunk < len(s.chunks) {
+		c := s.chunks[s.chunk]
+		if s.used+n <= len(c) {
+			result := c[s.used : s.used+n : s.used+n]
+			s.used += n
+			return result
+		}
+		s.chunk++
+		s.used = 0
+	}
+
+	size := minSlab
+	if len(s.chunks) > 0 {
+		size = 2 * len(s.chunks[len(s.chunks)-1])
+	}
+	if size < n {
+		size = n
+	}
+	s.chunks = append(s.chunks, make([]T, size))
+	s.chunk = len(s.chunks) - 1
+	s.used = n
+	return s.chunks[s.chunk][:n:n]
+}
+
+// copy returns a copy of items allocated from the slab.
+func (s *slab[T]) copy(items []T) []T {
+	result := s.alloc(len(items))
+	copy(result, items)
+	return result
+}
+
+// reset zeroes the used chunks, releasing what the nodes reference, and starts over from the first.
+func (s *slab[T]) reset() {
+	for i := 0; i <= s.chunk && i < len(s.chunks); i++ {
+		clear(s.chunks[i])
+	}
+	s.chunk = 0
+	s.used = 0
+}
@@ -0,0 +1,82 @@
+package jsonds
+
+import "github.com/binadel/jsonw/jsond"
+
+// Describe returns the description of the field and its subtree.
+// Nodes built by Each, MapOf and Frozen are described as Custom nodes, like the fields of Custom.
+func Describe(field Field) jsond.Node {
+	n := describe(&field.value)
+	n.Name = field.name
+	return n
+}
+
+// DescribeItem returns the description of the value and its subtree, like Describe.
+func DescribeItem(value Value) jsond.Node {
+	return describe(&value)
+}
+
+// Describe returns the description of the RootObject, an Object node.
+func (r RootObject) Describe() jsond.Node {
+	return jsond.Node{Kind: jsond.Object, Children: members(r)}
+}
+
+// Walk walks the description of the RootObject with v, without writing it.
+func (r RootObject) Walk(v jsond.Visitor) {
+	jsond.Walk(r.Describe(), v)
+}
+
+// Describe returns the description of the RootArray, an Array node.
+func (r RootArray) Describe() jsond.Node {
+	children := make([]jsond.Node, len(r))
+	for i := range r {
+		children[i] = describe(&r[i])
+	}
+	return jsond.Node{Kind: jsond.Array, Children: children}
+}
+
+// Walk walks the description of the RootArray with v, without writing it.
+func (r RootArray) Walk(v jsond.Visitor) {
+	jsond.Walk(r.Describe(), v)
+}
+
+func describe(v *Value) jsond.Node {
+	switch v.kind {
+	case kindObject:
+		return jsond.Node{Kind: jsond.Object, Children: members(v.children)}
+	case kindArray:
+		children := make([]jsond.Node, len(v.children))
+		for i := range v.children {
+			children[i] = describe(&v.children[i].value)
+		}
+		return jsond.Node{Kind: jsond.Array, Children: children}
+	case kindString:
+		return jsond.Node{Kind: jsond.String, Value: v.s}
+	case kindNumber:
+		return jsond.Node{Kind: jsond.Number, Value: v.s}
+	case kindInteger:
+		return jsond.Node{Kind: jsond.Integer, Value: v.integer()}
+	case kindFloat:
+		return jsond.Node{Kind: jsond.Float, Value: v.float()}
+	case kindBoolean:
+		return jsond.Node{Kind: jsond.Boolean, Value: v.boolean()}
+	case kindNull:
+		return jsond.Node{Kind: jsond.Null}
+	case kindAny:
+		return jsond.Node{Kind: jsond.Any, Value: v.a}
+	case kindObjectOf:
+		return jsond.Node{Kind: jsond.ObjectOf, Value: v.a}
+	case kindValueOf:
+		return jsond.Node{Kind: jsond.ValueOf, Value: v.a}
+	case kindCustom, kindCustomField:
+		return jsond.Node{Kind: jsond.Custom, Value: v.a}
+	}
+	panic("invalid value kind")
+}
+
+func members(fields []Field) []jsond.Node {
+	children := make([]jsond.Node, len(fields))
+	for i := range fields {
+		children[i] = Describe(fields[i])
+	}
+	return children
+}
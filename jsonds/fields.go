@@ -1,46 +1,64 @@
 package jsonds
 
+import "github.com/binadel/jsonw/jsoni"
+
 // Object creates a nested object field.
 func Object(name string, fields ...Field) Field {
-	return Field{kind: kindObject, name: name, fields: append([]Field{}, fields...)}
+	return Field{name, ObjectItem(fields...)}
 }
 
 // Array creates a nested array field.
 func Array(name string, values ...Value) Field {
-	return Field{kind: kindArray, name: name, values: append([]Value{}, values...)}
+	return Field{name, ArrayItem(values...)}
 }
 
 // String creates a string field.
 func String(name, value string) Field {
-	return Field{kind: kindString, name: name, s: value}
+	return Field{name, StringItem(value)}
 }
 
 // Number creates a number field.
 func Number(name, value string) Field {
-	return Field{kind: kindNumber, name: name, n: value}
+	return Field{name, NumberItem(value)}
 }
 
 // Integer creates an integer field.
 func Integer(name string, value int64) Field {
-	return Field{kind: kindInteger, name: name, i: value}
+	return Field{name, IntegerItem(value)}
 }
 
 // Float creates a float field.
 func Float(name string, value float64) Field {
-	return Field{kind: kindFloat, name: name, f: value}
+	return Field{name, FloatItem(value)}
 }
 
 // Boolean creates a boolean field.
 func Boolean(name string, value bool) Field {
-	return Field{kind: kindBoolean, name: name, b: value}
+	return Field{name, BooleanItem(value)}
 }
 
 // Null creates a null field.
 func Null(name string) Field {
-	return Field{kind: kindNull, name: name}
+	return Field{name, NullItem()}
 }
 
 // Any creates a dynamic field. Do not use it.
 func Any(name string, value any) Field {
-	return Field{kind: kindAny, name: name, a: value}
+	return Field{name, AnyItem(value)}
+}
+
+// ObjectOf creates a nested object field written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOf(name string, value jsoni.ObjectMarshaler) Field {
+	return Field{name, ObjectOfItem(value)}
+}
+
+// ValueOf creates a field written by a type implementing jsoni.ValueMarshaler.
+func ValueOf(name string, value jsoni.ValueMarshaler) Field {
+	return Field{name, ValueOfItem(value)}
+}
+
+// Custom creates a user-defined field written by fn, which receives the parent object writer
+// and should write a single member named name.
+func Custom(name string, fn func(writer *jsoni.ObjectWriter)) Field {
+	return Field{name, Value{kind: kindCustomField, a: fn}}
 }
@@ -0,0 +1,32 @@
+package jsonds
+
+import (
+	"github.com/binadel/jsonw/internal/frozen"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// Frozen wraps a field whose subtree never changes, so that it is encoded once, by the first build
+// writing it, and the cached bytes are spliced into later builds. The cache is safe for concurrent use.
+// A subtree that fails to encode is not cached and is written normally, reporting its error.
+func Frozen(field Field) Field {
+	f := frozen.Fields(func(w *jsoni.ObjectWriter) {
+		writeField(w, &field)
+	})
+	return Custom(field.name, func(writer *jsoni.ObjectWriter) {
+		if !f.WriteFields(writer) {
+			writeField(writer, &field)
+		}
+	})
+}
+
+// FrozenItem wraps a value whose subtree never changes, like Frozen.
+func FrozenItem(value Value) Value {
+	f := frozen.Value(func(w *jsoni.ArrayWriter) {
+		writeValue(w, &value)
+	})
+	return CustomItem(func(writer *jsoni.ArrayWriter) {
+		if !f.WriteValue(writer) {
+			writeValue(writer, &value)
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package jsonds
 
+import (
+	"math"
+
+	"github.com/binadel/jsonw/jsoni"
+)
+
 // NodeKind indicates the concrete kind of field or value.
 type NodeKind uint8
 
@@ -13,31 +19,63 @@ const (
 	kindBoolean
 	kindNull
 	kindAny
+	kindObjectOf    // written like any, kept apart to describe it
+	kindValueOf     // written like any, kept apart to describe it
+	kindCustom      // a value written by a func(*jsoni.ArrayWriter)
+	kindCustomField // a member written by a func(*jsoni.ObjectWriter), only found in a Field
 )
 
-// Field represents an object field.
-type Field struct {
-	kind   NodeKind
-	name   string
-	fields []Field // for object
-	values []Value // for array
-	s      string  // for string
-	n      string  // for number
-	i      int64   // for integer
-	f      float64 // for float
-	b      bool    // for bool
-	a      any     // for any
-}
-
-// Value represents an array value.
+// Value represents an array value. It is a tagged union: kind selects which payload slot is used,
+// so that every kind of node shares the same few slots.
 type Value struct {
-	kind   NodeKind
-	fields []Field // for object
-	values []Value // for array
-	s      string  // for string
-	n      string  // for number
-	i      int64   // for integer
-	f      float64 // for float
-	b      bool    // for bool
-	a      any     // for any
+	kind     NodeKind
+	s        string  // for string and number
+	x        uint64  // for integer, float and boolean, see the accessors
+	a        any     // for any, marshalers and custom
+	children []Field // for object members and array values, which are unnamed
+}
+
+// Field represents an object field: a named value.
+type Field struct {
+	name  string
+	value Value
+}
+
+func (v *Value) integer() int64 {
+	return int64(v.x)
+}
+
+func (v *Value) float() float64 {
+	return math.Float64frombits(v.x)
+}
+
+func (v *Value) boolean() bool {
+	return v.x != 0
+}
+
+func bits(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// items copies values into the children of an array.
+func items(children []Field, values []Value) []Field {
+	for i := range values {
+		children[i].value = values[i]
+	}
+	return children
+}
+
+// WriteField writes the field to an open object writer,
+// which embeds it in a document written with jsoni.
+func (f Field) WriteField(writer *jsoni.ObjectWriter) {
+	writeField(writer, &f)
+}
+
+// WriteValue writes the value to an array writer,
+// which embeds it in a document written with jsoni.
+func (v Value) WriteValue(writer *jsoni.ArrayWriter) {
+	writeValue(writer, &v)
 }
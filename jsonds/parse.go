@@ -0,0 +1,96 @@
+package jsonds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+// ErrInvalidJSON is reported by the parse functions for bytes that are not a valid JSON document.
+var ErrInvalidJSON = errors.New("jsonds: invalid JSON")
+
+// Parse parses a JSON object into an editable RootObject. Member order and duplicate members are
+// kept, member names keep their escaped form, which the writers write as it is, strings are
+// unescaped and numbers are Number nodes keeping their literal. Compact JSON written like jsoni
+// writes it, such as the output of Build, is written back byte for byte.
+func Parse(data []byte) (RootObject, error) {
+	v, err := ParseValue(data)
+	if err != nil {
+		return nil, err
+	}
+	if v.kind != kindObject {
+		return nil, errors.New("jsonds: the JSON root is not an object")
+	}
+	return RootObject(v.children), nil
+}
+
+// ParseArray parses a JSON array into an editable RootArray, like Parse.
+func ParseArray(data []byte) (RootArray, error) {
+	v, err := ParseValue(data)
+	if err != nil {
+		return nil, err
+	}
+	if v.kind != kindArray {
+		return nil, errors.New("jsonds: the JSON root is not an array")
+	}
+	r := make(RootArray, len(v.children))
+	for i := range v.children {
+		r[i] = v.children[i].value
+	}
+	return r, nil
+}
+
+// ParseValue parses any JSON value, like Parse, for example to be added to a tree with Insert.
+func ParseValue(data []byte) (Value, error) {
+	// The lexer accepts some invalid input, which is checked first.
+	if !json.Valid(data) {
+		var raw json.RawMessage
+		return Value{}, fmt.Errorf("%w: %v", ErrInvalidJSON, json.Unmarshal(data, &raw))
+	}
+	l := jlexer.Lexer{Data: data}
+	v := parseValue(&l)
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		return Value{}, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	return v, nil
+}
+
+func parseValue(l *jlexer.Lexer) Value {
+	switch l.CurrentToken() {
+	case jlexer.TokenString:
+		return StringItem(l.String())
+	case jlexer.TokenNumber:
+		return NumberItem(string(l.JsonNumber()))
+	case jlexer.TokenBool:
+		return BooleanItem(l.Bool())
+	case jlexer.TokenNull:
+		l.Null()
+		return NullItem()
+	}
+
+	if l.IsDelim('{') {
+		l.Delim('{')
+		var children []Field
+		for !l.IsDelim('}') {
+			// The name points into the input, which the caller may reuse.
+			name := strings.Clone(l.UnsafeFieldName(true))
+			l.WantColon()
+			children = append(children, Field{name, parseValue(l)})
+			l.WantComma()
+		}
+		l.Delim('}')
+		return Value{kind: kindObject, children: children}
+	}
+	l.Delim('[')
+	var children []Field
+	for !l.IsDelim(']') {
+		children = append(children, Field{value: parseValue(l)})
+		l.WantComma()
+	}
+	l.Delim(']')
+	return Value{kind: kindArray, children: children}
+}
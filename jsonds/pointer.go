@@ -0,0 +1,297 @@
+package jsonds
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPointer is reported for a string that is not a JSON Pointer (RFC 6901).
+	ErrInvalidPointer = errors.New("jsonds: invalid JSON pointer")
+	// ErrNotFound is reported for a pointer that doesn't reference a node of the tree, including
+	// pointers into Any, marshaler and Custom nodes, whose content is only known once written.
+	// A field made by Custom writes its own member, so it can't be taken out of its object either.
+	ErrNotFound = errors.New("jsonds: no node at pointer")
+	// ErrCustomMember is reported for a value made by CustomItem set or inserted as an object
+	// member, since it writes an array element.
+	ErrCustomMember = errors.New("jsonds: a custom value can't be an object member")
+)
+
+// Get returns the value referenced by the JSON Pointer (RFC 6901) pointer, such as "/tags/0".
+// The empty pointer references the root itself.
+func (r RootObject) Get(pointer string) (Value, error) {
+	return get(Value{kind: kindObject, children: r}, pointer)
+}
+
+// Set returns a copy of the RootObject in which the existing member or array element referenced
+// by pointer is replaced by value, like the replace operation of JSON Patch (RFC 6902).
+// Only the objects and arrays on the path are copied, the rest of the tree is shared with r,
+// which is left unchanged, and is also returned on error. The empty pointer replaces the whole
+// root with value, an object.
+func (r RootObject) Set(pointer string, value Value) (RootObject, error) {
+	v, err := edit(Value{kind: kindObject, children: r}, pointer, value, replace)
+	return RootObject(v.children), err
+}
+
+// Insert returns a copy of the RootObject with value added at pointer, like the add operation of
+// JSON Patch (RFC 6902): a new member is appended to its object, or replaces the member of the
+// same name, and an array element is inserted before the one at its index, or appended if the
+// last token is "-". Like Set, it only copies the objects and arrays on the path.
+func (r RootObject) Insert(pointer string, value Value) (RootObject, error) {
+	v, err := edit(Value{kind: kindObject, children: r}, pointer, value, add)
+	return RootObject(v.children), err
+}
+
+// Delete returns a copy of the RootObject without the member or array element referenced by pointer,
+// like the remove operation of JSON Patch (RFC 6902). Like Set, it only copies the objects and
+// arrays on the path.
+func (r RootObject) Delete(pointer string) (RootObject, error) {
+	v, err := edit(Value{kind: kindObject, children: r}, pointer, Value{}, remove)
+	return RootObject(v.children), err
+}
+
+// Get returns the value referenced by pointer, like RootObject.Get.
+func (r RootArray) Get(pointer string) (Value, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil || len(tokens) == 0 {
+		return Value{kind: kindArray, children: items(make([]Field, len(r)), r)}, err
+	}
+	// Indexing the root directly saves converting it to a value.
+	i, ok := index(tokens[0], len(r))
+	if !ok {
+		return Value{}, fmt.Errorf("%w: %s", ErrNotFound, pointer)
+	}
+	return lookup(r[i], tokens[1:], pointer)
+}
+
+// Set returns a copy of the RootArray with the node at pointer replaced, like RootObject.Set.
+// The empty pointer replaces the whole root with value, an array.
+func (r RootArray) Set(pointer string, value Value) (RootArray, error) {
+	return r.edit(pointer, value, replace)
+}
+
+// Insert returns a copy of the RootArray with value added at pointer, like RootObject.Insert.
+func (r RootArray) Insert(pointer string, value Value) (RootArray, error) {
+	return r.edit(pointer, value, add)
+}
+
+// Delete returns a copy of the RootArray without the node at pointer, like RootObject.Delete.
+func (r RootArray) Delete(pointer string) (RootArray, error) {
+	return r.edit(pointer, Value{}, remove)
+}
+
+func (r RootArray) edit(pointer string, value Value, op operation) (RootArray, error) {
+	v, err := edit(Value{kind: kindArray, children: items(make([]Field, len(r)), r)}, pointer, value, op)
+	if err != nil {
+		return r, err
+	}
+	result := make(RootArray, len(v.children))
+	for i := range v.children {
+		result[i] = v.children[i].value
+	}
+	return result, nil
+}
+
+// operation is an edit of the node referenced by a pointer, named after the JSON Patch operations.
+type operation uint8
+
+const (
+	replace operation = iota
+	add
+	remove
+)
+
+// get returns the node of root referenced by pointer.
+func get(root Value, pointer string) (Value, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return Value{}, err
+	}
+	return lookup(root, tokens, pointer)
+}
+
+func lookup(v Value, tokens []string, pointer string) (Value, error) {
+	for _, token := range tokens {
+		i, ok := child(&v, token)
+		if !ok {
+			return Value{}, fmt.Errorf("%w: %s", ErrNotFound, pointer)
+		}
+		v = v.children[i].value
+	}
+	if v.kind == kindCustomField {
+		return Value{}, fmt.Errorf("%w: %s", ErrNotFound, pointer)
+	}
+	return v, nil
+}
+
+// edit returns a copy of root with op applied to the node referenced by pointer,
+// copying the containers on the path only. On error, root is returned unchanged.
+func edit(root Value, pointer string, value Value, op operation) (Value, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return root, err
+	}
+	if len(tokens) == 0 {
+		if op == remove {
+			return root, errors.New("jsonds: the root can't be removed")
+		}
+		if value.kind != root.kind {
+			return root, errors.New("jsonds: the root can only be replaced by a value of the same kind")
+		}
+		return value, nil
+	}
+	if value.kind == kindCustom {
+		if parent, err := lookup(root, tokens[:len(tokens)-1], pointer); err == nil && parent.kind == kindObject {
+			return root, fmt.Errorf("%w: %s", ErrCustomMember, pointer)
+		}
+	}
+	result, ok := editAt(root, tokens, value, op)
+	if !ok {
+		return root, fmt.Errorf("%w: %s", ErrNotFound, pointer)
+	}
+	return result, nil
+}
+
+func editAt(v Value, tokens []string, value Value, op operation) (Value, bool) {
+	if len(tokens) == 1 {
+		switch op {
+		case replace:
+			return replaceChild(v, tokens[0], value)
+		case add:
+			return addChild(v, tokens[0], value)
+		}
+		return removeChild(v, tokens[0])
+	}
+	i, ok := child(&v, tokens[0])
+	if !ok {
+		return v, false
+	}
+	edited, ok := editAt(v.children[i].value, tokens[1:], value, op)
+	if !ok {
+		return v, false
+	}
+	children := append([]Field{}, v.children...)
+	children[i].value = edited
+	v.children = children
+	return v, true
+}
+
+// replaceChild replaces the existing child of v referenced by token.
+func replaceChild(v Value, token string, value Value) (Value, bool) {
+	i, ok := child(&v, token)
+	if !ok {
+		return v, false
+	}
+	children := append([]Field{}, v.children...)
+	children[i].value = value
+	v.children = children
+	return v, true
+}
+
+// addChild adds value as the member token of an object, replacing a member of the same name,
+// or inserts it in an array at the index token. A new member name is escaped to be written.
+func addChild(v Value, token string, value Value) (Value, bool) {
+	if v.kind == kindObject {
+		if _, ok := child(&v, token); ok {
+			return replaceChild(v, token, value)
+		}
+		children := make([]Field, len(v.children), len(v.children)+1)
+		copy(children, v.children)
+		v.children = append(children, Field{escapeName(token), value})
+		return v, true
+	}
+	if v.kind != kindArray {
+		return v, false
+	}
+
+	i, ok := len(v.children), token == "-"
+	if !ok {
+		if i, ok = index(token, len(v.children)+1); !ok {
+			return v, false
+		}
+	}
+	children := make([]Field, 0, len(v.children)+1)
+	children = append(children, v.children[:i]...)
+	children = append(children, Field{value: value})
+	v.children = append(children, v.children[i:]...)
+	return v, true
+}
+
+// removeChild removes the child of v referenced by token.
+func removeChild(v Value, token string) (Value, bool) {
+	i, ok := child(&v, token)
+	if !ok {
+		return v, false
+	}
+	children := make([]Field, 0, len(v.children)-1)
+	children = append(children, v.children[:i]...)
+	v.children = append(children, v.children[i+1:]...)
+	return v, true
+}
+
+// child returns the index in the children of v of the first member named token, for an object,
+// or of the element at the index token, for an array. Member names are kept in their escaped
+// form, as they are written, so they are unescaped to be compared with the key token.
+func child(v *Value, token string) (int, bool) {
+	switch v.kind {
+	case kindObject:
+		for i := range v.children {
+			if unescapeName(v.children[i].name) == token {
+				return i, true
+			}
+		}
+	case kindArray:
+		return index(token, len(v.children))
+	}
+	return 0, false
+}
+
+// index parses an array index token, which has no leading zeros and must be less than n.
+func index(token string, n int) (int, bool) {
+	if token == "" || len(token) > 1 && token[0] == '0' || token[0] == '+' || token[0] == '-' {
+		return 0, false
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= n {
+		return 0, false
+	}
+	return i, true
+}
+
+var (
+	escaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// escapePointer returns a key as a reference token of a JSON Pointer.
+func escapePointer(key string) string {
+	if !strings.ContainsAny(key, "~/") {
+		return key
+	}
+	return escaper.Replace(key)
+}
+
+// parsePointer returns the unescaped reference tokens of a JSON Pointer.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if !strings.Contains(token, "~") {
+			continue
+		}
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, pointer)
+			}
+		}
+		tokens[i] = unescaper.Replace(token)
+	}
+	return tokens, nil
+}
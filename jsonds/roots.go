@@ -6,6 +6,8 @@ import (
 )
 
 // RootObject represents a json root object.
+// Writing a tree doesn't modify it, so a built tree can be shared and written by several
+// goroutines at once, as long as its Any and marshaler values and custom functions allow it.
 type RootObject []Field
 
 // New creates a new root object.
@@ -18,14 +20,31 @@ func (r RootObject) Build() ([]byte, error) {
 	var jw jwriter.Writer
 	ow := jsoni.NewObjectWriter(&jw)
 	ow.Open()
-	for _, f := range r {
-		writeField(&ow, &f)
-	}
+	r.WriteFields(&ow)
 	ow.Close()
 	return ow.BuildBytes()
 }
 
+// WriteFields writes the fields of the RootObject to an open object writer,
+// which embeds them in a document written with jsoni.
+func (r RootObject) WriteFields(ow *jsoni.ObjectWriter) {
+	for i := range r {
+		writeField(ow, &r[i])
+	}
+}
+
+// BuildInto writes the RootObject to an existing writer, such as a pooled one,
+// leaving the output there, and returns the first error recorded while writing.
+func (r RootObject) BuildInto(jw *jwriter.Writer) error {
+	ow := jsoni.NewObjectWriter(jw)
+	ow.Open()
+	r.WriteFields(&ow)
+	ow.Close()
+	return ow.Err()
+}
+
 // RootArray represents a json root array.
+// Like a RootObject, it can be shared and written by several goroutines at once.
 type RootArray []Value
 
 // NewArray creates a new root array.
@@ -38,43 +57,60 @@ func (r RootArray) Build() ([]byte, error) {
 	var jw jwriter.Writer
 	aw := jsoni.NewArrayWriter(&jw)
 	aw.Open()
-	for _, v := range r {
-		writeValue(&aw, &v)
-	}
+	r.WriteValues(&aw)
 	aw.Close()
 	return aw.BuildBytes()
 }
 
+// WriteValues writes the values of the RootArray to an open array writer,
+// which embeds them in a document written with jsoni.
+func (r RootArray) WriteValues(aw *jsoni.ArrayWriter) {
+	for i := range r {
+		writeValue(aw, &r[i])
+	}
+}
+
+// BuildInto writes the RootArray to an existing writer, such as a pooled one,
+// leaving the output there, and returns the first error recorded while writing.
+func (r RootArray) BuildInto(jw *jwriter.Writer) error {
+	aw := jsoni.NewArrayWriter(jw)
+	aw.Open()
+	r.WriteValues(&aw)
+	aw.Close()
+	return aw.Err()
+}
+
 func writeField(w *jsoni.ObjectWriter, f *Field) {
-	switch f.kind {
+	v := &f.value
+	switch v.kind {
 	case kindObject:
-		obj := w.ObjectField(f.name)
-		obj.Open()
-		for i := range f.fields {
-			writeField(&obj, &f.fields[i])
-		}
-		obj.Close()
+		w.Object(f.name, func(obj *jsoni.ObjectWriter) {
+			for i := range v.children {
+				writeField(obj, &v.children[i])
+			}
+		})
 	case kindArray:
-		arr := w.ArrayField(f.name)
-		arr.Open()
-		for i := range f.values {
-			writeValue(&arr, &f.values[i])
-		}
-		arr.Close()
+		w.Array(f.name, func(arr *jsoni.ArrayWriter) {
+			for i := range v.children {
+				writeValue(arr, &v.children[i].value)
+			}
+		})
 	case kindString:
-		w.StringField(f.name, f.s)
+		w.StringField(f.name, v.s)
 	case kindNumber:
-		w.NumberField(f.name, f.n)
+		w.NumberField(f.name, v.s)
 	case kindInteger:
-		w.IntegerField(f.name, f.i)
+		w.IntegerField(f.name, v.integer())
 	case kindFloat:
-		w.FloatField(f.name, f.f)
+		w.FloatField(f.name, v.float())
 	case kindBoolean:
-		w.BooleanField(f.name, f.b)
+		w.BooleanField(f.name, v.boolean())
 	case kindNull:
 		w.NullField(f.name)
-	case kindAny:
-		w.AnyField(f.name, f.a)
+	case kindAny, kindObjectOf, kindValueOf:
+		w.AnyField(f.name, v.a)
+	case kindCustomField:
+		v.a.(func(writer *jsoni.ObjectWriter))(w)
 	default:
 		panic("invalid field kind")
 	}
@@ -83,33 +119,33 @@ func writeField(w *jsoni.ObjectWriter, f *Field) {
 func writeValue(w *jsoni.ArrayWriter, v *Value) {
 	switch v.kind {
 	case kindObject:
-		obj := w.ObjectValue()
-		obj.Open()
-		for i := range v.fields {
-			writeField(&obj, &v.fields[i])
-		}
-		obj.Close()
+		w.Object(func(obj *jsoni.ObjectWriter) {
+			for i := range v.children {
+				writeField(obj, &v.children[i])
+			}
+		})
 	case kindArray:
-		arr := w.ArrayValue()
-		arr.Open()
-		for i := range v.values {
-			writeValue(&arr, &v.values[i])
-		}
-		arr.Close()
+		w.Array(func(arr *jsoni.ArrayWriter) {
+			for i := range v.children {
+				writeValue(arr, &v.children[i].value)
+			}
+		})
 	case kindString:
 		w.StringValue(v.s)
 	case kindNumber:
-		w.NumberValue(v.n)
+		w.NumberValue(v.s)
 	case kindInteger:
-		w.IntegerValue(v.i)
+		w.IntegerValue(v.integer())
 	case kindFloat:
-		w.FloatValue(v.f)
+		w.FloatValue(v.float())
 	case kindBoolean:
-		w.BooleanValue(v.b)
+		w.BooleanValue(v.boolean())
 	case kindNull:
 		w.NullValue()
-	case kindAny:
+	case kindAny, kindObjectOf, kindValueOf:
 		w.AnyValue(v.a)
+	case kindCustom:
+		v.a.(func(writer *jsoni.ArrayWriter))(w)
 	default:
 		panic("invalid value kind")
 	}
@@ -1,13 +1,19 @@
 package jsonds
 
+import (
+	"math"
+
+	"github.com/binadel/jsonw/jsoni"
+)
+
 // ObjectItem creates a nested object value.
 func ObjectItem(fields ...Field) Value {
-	return Value{kind: kindObject, fields: append([]Field{}, fields...)}
+	return Value{kind: kindObject, children: append([]Field{}, fields...)}
 }
 
 // ArrayItem creates a nested array value.
 func ArrayItem(values ...Value) Value {
-	return Value{kind: kindArray, values: append([]Value{}, values...)}
+	return Value{kind: kindArray, children: items(make([]Field, len(values)), values)}
 }
 
 // StringItem creates a string value.
@@ -17,22 +23,22 @@ func StringItem(value string) Value {
 
 // NumberItem creates a number value.
 func NumberItem(value string) Value {
-	return Value{kind: kindNumber, n: value}
+	return Value{kind: kindNumber, s: value}
 }
 
 // IntegerItem creates an integer value.
 func IntegerItem(value int64) Value {
-	return Value{kind: kindInteger, i: value}
+	return Value{kind: kindInteger, x: uint64(value)}
 }
 
 // FloatItem creates a float value.
 func FloatItem(value float64) Value {
-	return Value{kind: kindFloat, f: value}
+	return Value{kind: kindFloat, x: math.Float64bits(value)}
 }
 
 // BooleanItem creates a boolean value.
 func BooleanItem(value bool) Value {
-	return Value{kind: kindBoolean, b: value}
+	return Value{kind: kindBoolean, x: bits(value)}
 }
 
 // NullItem creates a null value.
@@ -44,3 +50,19 @@ func NullItem() Value {
 func AnyItem(v any) Value {
 	return Value{kind: kindAny, a: v}
 }
+
+// ObjectOfItem creates a nested object value written by a type implementing jsoni.ObjectMarshaler.
+func ObjectOfItem(value jsoni.ObjectMarshaler) Value {
+	return Value{kind: kindObjectOf, a: value}
+}
+
+// ValueOfItem creates a value written by a type implementing jsoni.ValueMarshaler.
+func ValueOfItem(value jsoni.ValueMarshaler) Value {
+	return Value{kind: kindValueOf, a: value}
+}
+
+// CustomItem creates a user-defined value written by fn, which receives the parent array writer
+// and should write a single value.
+func CustomItem(fn func(writer *jsoni.ArrayWriter)) Value {
+	return Value{kind: kindCustom, a: fn}
+}
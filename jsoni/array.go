@@ -1,12 +1,30 @@
 package jsoni
 
-import "github.com/mailru/easyjson/jwriter"
+import (
+	"github.com/binadel/jsonw/internal/number"
+	"github.com/mailru/easyjson/jwriter"
+)
 
 // ArrayWriter builds a JSON array manually, supporting values of various types,
 // including nested objects and arrays.
 type ArrayWriter struct {
-	writer     *jwriter.Writer
-	needsComma bool
+	writer      *jwriter.Writer
+	needsComma  bool
+	start       Mark          // position before the value, for omit-empty writers
+	parent      *bool         // parent's needsComma, set only for omit-empty writers
+	parentIndex *int          // parent's index, set only for omit-empty writers of an ArrayWriter
+	objects     *ObjectWriter // reused by Object and EachObject
+	arrays      *ArrayWriter  // reused by Array and EachArray
+	path        *path         // shared key and index chain, nil unless tracking
+	depth       int           // number of path segments leading to this array
+	index       int           // number of values written since Open
+	single      bool          // writes one value located at depth, see valueWriter
+}
+
+// valueWriter returns an ArrayWriter for writing a single value in place,
+// such as the value of an object member, located at the given path depth.
+func valueWriter(writer *jwriter.Writer, p *path, depth int) ArrayWriter {
+	return ArrayWriter{writer: writer, path: p, depth: depth, single: true}
 }
 
 // NewArrayWriter creates a new ArrayWriter given an optional writer from its parent node.
@@ -26,6 +44,7 @@ func (w *ArrayWriter) Open() {
 	w.writer.RawByte(openBracket)
 
 	w.needsComma = false
+	w.index = 0
 }
 
 // ObjectValue appends a new object to the array and returns its writer for further modifications.
@@ -36,7 +55,10 @@ func (w *ArrayWriter) ObjectValue() ObjectWriter {
 
 	w.needsComma = true
 
-	return NewObjectWriter(w.writer)
+	obj := NewObjectWriter(w.writer)
+	obj.path, obj.depth = w.enter()
+	w.index++
+	return obj
 }
 
 // ArrayValue appends a new nested array and returns its writer for further modifications.
@@ -47,7 +69,98 @@ func (w *ArrayWriter) ArrayValue() ArrayWriter {
 
 	w.needsComma = true
 
-	return NewArrayWriter(w.writer)
+	arr := NewArrayWriter(w.writer)
+	arr.path, arr.depth = w.enter()
+	w.index++
+	return arr
+}
+
+// Object appends a new object whose fields are written by fn.
+// The object is opened before fn is called and closed after it returns,
+// so the output stays balanced however fn returns.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ArrayWriter) Object(fn func(obj *ObjectWriter)) {
+	obj := w.nestedObject()
+	obj.Open()
+	fn(obj)
+	obj.Close()
+}
+
+// Array appends a new nested array whose values are written by fn.
+// The array is opened before fn is called and closed after it returns,
+// so the output stays balanced however fn returns.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ArrayWriter) Array(fn func(arr *ArrayWriter)) {
+	arr := w.nestedArray()
+	arr.Open()
+	fn(arr)
+	arr.Close()
+}
+
+// EachObject appends n objects, calling fn with the index and writer of each.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ArrayWriter) EachObject(n int, fn func(i int, obj *ObjectWriter)) {
+	for i := 0; i < n; i++ {
+		obj := w.nestedObject()
+		obj.Open()
+		fn(i, obj)
+		obj.Close()
+	}
+}
+
+// EachArray appends n nested arrays, calling fn with the index and writer of each.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ArrayWriter) EachArray(n int, fn func(i int, arr *ArrayWriter)) {
+	for i := 0; i < n; i++ {
+		arr := w.nestedArray()
+		arr.Open()
+		fn(i, arr)
+		arr.Close()
+	}
+}
+
+// nestedObject appends a new object using the writer cached for Object and EachObject.
+func (w *ArrayWriter) nestedObject() *ObjectWriter {
+	if w.objects == nil {
+		w.objects = &ObjectWriter{}
+	}
+
+	obj := w.objects
+	obj.reuse(w.ObjectValue())
+	return obj
+}
+
+// nestedArray appends a new nested array using the writer cached for Array and EachArray.
+func (w *ArrayWriter) nestedArray() *ArrayWriter {
+	if w.arrays == nil {
+		w.arrays = &ArrayWriter{}
+	}
+
+	arr := w.arrays
+	arr.reuse(w.ArrayValue())
+	return arr
+}
+
+// ObjectValueOmitEmpty appends a new object like ObjectValue, except that
+// the object is removed on Close if no fields were written.
+func (w *ArrayWriter) ObjectValueOmitEmpty() ObjectWriter {
+	start := w.Mark()
+	obj := w.ObjectValue()
+	obj.start = start
+	obj.parent = &w.needsComma
+	obj.parentIndex = &w.index
+	return obj
+}
+
+// ArrayValueOmitEmpty appends a new nested array like ArrayValue, except that
+// the array is removed on Close if no values were written.
+func (w *ArrayWriter) ArrayValueOmitEmpty() ArrayWriter {
+	start := w.Mark()
+	arr := w.ArrayValue()
+	arr.start = start
+	arr.parent = &w.needsComma
+	arr.parentIndex = &w.index
+	return arr
 }
 
 // StringValue appends a string value to the array.
@@ -59,10 +172,17 @@ func (w *ArrayWriter) StringValue(value string) {
 	w.writer.String(value)
 
 	w.needsComma = true
+	w.index++
 }
 
 // NumberValue appends a number value to the array.
+// A value that is not a valid JSON number is reported as ErrInvalidNumber.
 func (w *ArrayWriter) NumberValue(value string) {
+	if !number.Valid(value) {
+		w.fail(ErrInvalidNumber)
+		return
+	}
+
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
@@ -70,6 +190,7 @@ func (w *ArrayWriter) NumberValue(value string) {
 	w.writer.RawString(value)
 
 	w.needsComma = true
+	w.index++
 }
 
 // IntegerValue appends an integer value to the array.
@@ -81,10 +202,17 @@ func (w *ArrayWriter) IntegerValue(value int64) {
 	w.writer.Int64(value)
 
 	w.needsComma = true
+	w.index++
 }
 
 // FloatValue appends a float value to the array.
+// NaN and infinite values are reported as ErrUnsupportedFloat.
 func (w *ArrayWriter) FloatValue(value float64) {
+	if !validFloat(value) {
+		w.fail(ErrUnsupportedFloat)
+		return
+	}
+
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
@@ -92,6 +220,7 @@ func (w *ArrayWriter) FloatValue(value float64) {
 	w.writer.Float64(value)
 
 	w.needsComma = true
+	w.index++
 }
 
 // BooleanValue appends a boolean value to the array.
@@ -103,6 +232,49 @@ func (w *ArrayWriter) BooleanValue(value bool) {
 	w.writer.Bool(value)
 
 	w.needsComma = true
+	w.index++
+}
+
+// RawValue appends a value that is already encoded JSON, such as one cached from a previous write.
+// It is written as it is, without validation, and an empty value is written as null.
+func (w *ArrayWriter) RawValue(value []byte) {
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	w.writer.Raw(value, nil)
+
+	w.needsComma = true
+	w.index++
+}
+
+// startValue starts a value written by the caller, and returns the path state for it.
+// The caller increments the index once the value is written.
+func (w *ArrayWriter) startValue() (*path, int) {
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	w.needsComma = true
+
+	return w.enter()
+}
+
+// anyFloat appends a float value formatted like encoding/json formats it.
+func (w *ArrayWriter) anyFloat(value float64) {
+	if !validFloat(value) {
+		w.fail(ErrUnsupportedFloat)
+		return
+	}
+
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	_ = writeFloat(w.writer, value, 64)
+
+	w.needsComma = true
+	w.index++
 }
 
 // NullValue appends a JSON null to the array.
@@ -114,24 +286,161 @@ func (w *ArrayWriter) NullValue() {
 	w.writer.Raw(nullValue, nil)
 
 	w.needsComma = true
+	w.index++
 }
 
 // AnyValue appends a value of any type, automatically detecting its JSON representation.
+// Values implementing ObjectMarshaler or ValueMarshaler write themselves.
 func (w *ArrayWriter) AnyValue(value any) {
+	start := w.Mark()
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
 
-	writeAny(w.writer, value)
+	p, depth := w.enter()
+	if err := writeAny(w.writer, p, depth, value); err != nil {
+		w.Rollback(start)
+		w.fail(err)
+		return
+	}
 
 	w.needsComma = true
+	w.index++
+}
+
+// encodeValue appends a value nested in another one, written like encoding/json writes it,
+// see encodeAny.
+func (w *ArrayWriter) encodeValue(state encodeState, value any) {
+	start := w.Mark()
+	p, depth := w.startValue()
+	if err := encodeAny(w.writer, p, depth, state, value); err != nil {
+		w.Rollback(start)
+		w.fail(err)
+		return
+	}
+
+	w.index++
 }
 
 // Close finishes the JSON array by writing ']'.
+// An empty array created by ArrayFieldOmitEmpty or ArrayValueOmitEmpty is removed instead.
 func (w *ArrayWriter) Close() {
+	if w.parent != nil && !w.needsComma {
+		truncate(w.writer, w.start.size)
+		*w.parent = w.start.needsComma
+		w.parent = nil
+		if w.parentIndex != nil {
+			*w.parentIndex = w.start.index
+			w.parentIndex = nil
+		}
+		return
+	}
+
 	w.writer.RawByte(closeBracket)
 
 	w.needsComma = false
+	w.index = 0
+}
+
+// reuse replaces the writer state with next, keeping the writers cached for Object and Array,
+// and only storing the pointers that change, like ObjectWriter.reuse.
+func (w *ArrayWriter) reuse(next ArrayWriter) {
+	if w.writer != next.writer {
+		w.writer = next.writer
+	}
+	if w.parent != next.parent {
+		w.parent = next.parent
+	}
+	if w.parentIndex != next.parentIndex {
+		w.parentIndex = next.parentIndex
+	}
+	if w.path != next.path {
+		w.path = next.path
+	}
+	w.needsComma, w.start, w.depth, w.index, w.single = next.needsComma, next.start, next.depth, next.index, next.single
+}
+
+// Mark returns the current position, to be passed to Rollback.
+func (w *ArrayWriter) Mark() Mark {
+	return Mark{size: w.writer.Size(), needsComma: w.needsComma, index: w.index}
+}
+
+// Rollback discards everything written since the given mark was taken.
+// The mark must come from this writer and must not be older than its Open.
+func (w *ArrayWriter) Rollback(mark Mark) {
+	truncate(w.writer, mark.size)
+
+	w.needsComma = mark.needsComma
+	w.index = mark.index
+}
+
+// TrackPath turns on path tracking for this writer and the writers nested in it,
+// so that Path and the errors they report carry the location in the document.
+// It must be called on the root writer, before anything is written.
+func (w *ArrayWriter) TrackPath() {
+	w.path = &path{}
+	w.depth = 0
+}
+
+// Path returns the JSON Pointer (RFC 6901) of this array within the document,
+// which is empty for the root and when path tracking is off.
+func (w *ArrayWriter) Path() string {
+	if w.path == nil {
+		return ""
+	}
+	return w.path.pointer(w.depth)
+}
+
+// enter records that the next value is being written,
+// and returns the path state for its nested writer.
+func (w *ArrayWriter) enter() (*path, int) {
+	if w.path == nil {
+		return nil, 0
+	}
+	if w.single {
+		return w.path, w.depth
+	}
+	w.path.set(w.depth, indexSegment(w.index))
+	return w.path, w.depth + 1
+}
+
+// fail records err for the next value, unless an error was already recorded.
+func (w *ArrayWriter) fail(err error) {
+	if w.writer.Error != nil {
+		return
+	}
+	e := &Error{Err: err}
+	switch {
+	case w.path == nil:
+	case w.single:
+		e.Path = w.path.pointer(w.depth)
+	default:
+		w.path.set(w.depth, indexSegment(w.index))
+		e.Path = w.path.pointer(w.depth + 1)
+	}
+	w.writer.Error = e
+}
+
+// Err returns the first error recorded while writing, if any.
+func (w *ArrayWriter) Err() error {
+	return w.writer.Error
+}
+
+// Len returns the number of bytes written so far to the underlying writer.
+func (w *ArrayWriter) Len() int {
+	return w.writer.Size()
+}
+
+// Bytes returns the bytes written so far to the underlying writer, without consuming them.
+// The underlying writer is shared by the whole document, so this includes the output of
+// parent and sibling writers. The slice must not be modified and is only valid until the next write.
+func (w *ArrayWriter) Bytes() []byte {
+	return contents(w.writer)
+}
+
+// String returns the bytes written so far to the underlying writer as a string, without consuming them.
+func (w *ArrayWriter) String() string {
+	return string(contents(w.writer))
 }
 
 // BuildBytes returns the resulting JSON bytes.
@@ -2,6 +2,8 @@ package jsoni
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/mailru/easyjson/jwriter"
@@ -717,3 +719,176 @@ func TestArrayWriter_DeepNesting(t *testing.T) {
 		t.Error("Nested object level field not found or incorrect")
 	}
 }
+
+func TestArrayWriter_Rollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(arr *ArrayWriter)
+		expected string
+	}{
+		{
+			name: "first value",
+			write: func(arr *ArrayWriter) {
+				mark := arr.Mark()
+				arr.StringValue("drop")
+				arr.Rollback(mark)
+				arr.IntegerValue(1)
+			},
+			expected: `[1]`,
+		},
+		{
+			name: "later values",
+			write: func(arr *ArrayWriter) {
+				arr.IntegerValue(1)
+				mark := arr.Mark()
+				arr.IntegerValue(2)
+				arr.IntegerValue(3)
+				arr.Rollback(mark)
+				arr.IntegerValue(4)
+			},
+			expected: `[1,4]`,
+		},
+		{
+			name: "large content spanning chunks",
+			write: func(arr *ArrayWriter) {
+				arr.StringValue("keep")
+				mark := arr.Mark()
+				for i := 0; i < 10000; i++ {
+					arr.IntegerValue(int64(i))
+				}
+				arr.Rollback(mark)
+				arr.BooleanValue(true)
+			},
+			expected: `["keep",true]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := NewArrayWriter(nil)
+			arr.Open()
+			tt.write(&arr)
+			arr.Close()
+
+			result, err := arr.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+
+			if string(result) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(result))
+			}
+		})
+	}
+}
+
+func TestArrayWriter_OmitEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(arr *ArrayWriter)
+		expected string
+	}{
+		{
+			name: "empty object as first value",
+			write: func(arr *ArrayWriter) {
+				obj := arr.ObjectValueOmitEmpty()
+				obj.Open()
+				obj.Close()
+				arr.IntegerValue(1)
+			},
+			expected: `[1]`,
+		},
+		{
+			name: "empty array as later value",
+			write: func(arr *ArrayWriter) {
+				arr.IntegerValue(1)
+				nested := arr.ArrayValueOmitEmpty()
+				nested.Open()
+				nested.Close()
+				arr.IntegerValue(2)
+			},
+			expected: `[1,2]`,
+		},
+		{
+			name: "non-empty array is kept",
+			write: func(arr *ArrayWriter) {
+				nested := arr.ArrayValueOmitEmpty()
+				nested.Open()
+				nested.NullValue()
+				nested.Close()
+			},
+			expected: `[[null]]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := NewArrayWriter(nil)
+			arr.Open()
+			tt.write(&arr)
+			arr.Close()
+
+			result, err := arr.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+
+			if string(result) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(result))
+			}
+		})
+	}
+}
+
+func TestArrayWriter_Closures(t *testing.T) {
+	arr := NewArrayWriter(nil)
+	arr.Open()
+	arr.Object(func(obj *ObjectWriter) {
+		obj.StringField("name", "John")
+	})
+	arr.Array(func(nested *ArrayWriter) {
+		nested.IntegerValue(1)
+	})
+	arr.EachObject(2, func(i int, obj *ObjectWriter) {
+		obj.IntegerField("index", int64(i))
+	})
+	arr.EachArray(2, func(i int, nested *ArrayWriter) {
+		nested.EachArray(i, func(j int, inner *ArrayWriter) {
+			inner.IntegerValue(int64(j))
+		})
+	})
+	arr.EachObject(0, func(int, *ObjectWriter) {
+		t.Error("EachObject called fn for n = 0")
+	})
+	arr.Close()
+
+	result, err := arr.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `[{"name":"John"},[1],{"index":0},{"index":1},[],[[0]]]`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
+
+func TestArrayWriter_RawValue(t *testing.T) {
+	arr := NewArrayWriter(nil)
+	arr.TrackPath()
+	arr.Open()
+	arr.RawValue([]byte(`{"a":[1,2]}`))
+	arr.RawValue(nil)
+	arr.RawValue([]byte(`"x"`))
+	arr.FloatValue(math.Inf(1))
+	arr.Close()
+
+	if result := arr.String(); result != `[{"a":[1,2]},null,"x"]` {
+		t.Errorf("Expected %q, got %q", `[{"a":[1,2]},null,"x"]`, result)
+	}
+
+	var e *Error
+	if _, err := arr.BuildBytes(); !errors.As(err, &e) || e.Path != "/3" {
+		t.Errorf("Expected an error at /3, got %v", err)
+	}
+}
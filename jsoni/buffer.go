@@ -0,0 +1,31 @@
+package jsoni
+
+import "github.com/mailru/easyjson/jwriter"
+
+// truncate discards everything written to the writer after its first size bytes.
+func truncate(writer *jwriter.Writer, size int) {
+	buf := &writer.Buffer
+	head := buf.Size() - len(buf.Buf)
+	if size >= head {
+		buf.Buf = buf.Buf[:size-head]
+		return
+	}
+
+	// The mark lies in an earlier chunk, which the buffer does not expose,
+	// so the contents are flattened and the kept prefix is written back.
+	data := buf.BuildBytes()
+	buf.AppendBytes(data[:size])
+}
+
+// contents returns everything written to the writer so far, leaving it in place.
+func contents(writer *jwriter.Writer) []byte {
+	buf := &writer.Buffer
+	if buf.Size() == len(buf.Buf) {
+		return buf.Buf
+	}
+
+	// Building flattens the chunks but empties the buffer, so the flat copy is written back.
+	data := buf.BuildBytes()
+	buf.AppendBytes(data)
+	return data
+}
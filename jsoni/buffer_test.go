@@ -0,0 +1,78 @@
+package jsoni
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestObjectWriter_Introspection(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.Open()
+	obj.StringField("name", "John")
+
+	if got := obj.String(); got != `{"name":"John"` {
+		t.Errorf("Expected %q, got %q", `{"name":"John"`, got)
+	}
+	if got := obj.Len(); got != len(`{"name":"John"`) {
+		t.Errorf("Expected length %d, got %d", len(`{"name":"John"`), got)
+	}
+	if err := obj.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	obj.IntegerField("age", 30)
+	obj.Close()
+
+	if got := string(obj.Bytes()); got != `{"name":"John","age":30}` {
+		t.Errorf("Expected %q, got %q", `{"name":"John","age":30}`, got)
+	}
+
+	result, err := obj.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+	if string(result) != `{"name":"John","age":30}` {
+		t.Errorf("Expected %q, got %q", `{"name":"John","age":30}`, string(result))
+	}
+}
+
+func TestArrayWriter_Introspection(t *testing.T) {
+	arr := NewArrayWriter(nil)
+	arr.Open()
+	arr.FloatValue(math.NaN())
+
+	if arr.Err() == nil {
+		t.Error("Expected an error after writing NaN")
+	}
+
+	arr.IntegerValue(1)
+	if got := arr.String(); got != `[1` {
+		t.Errorf("Expected %q, got %q", `[1`, got)
+	}
+}
+
+func TestIntrospection_LargeContent(t *testing.T) {
+	arr := NewArrayWriter(nil)
+	arr.Open()
+	for i := 0; i < 10000; i++ {
+		arr.StringValue("value")
+	}
+
+	expected := "[" + strings.Repeat(`"value",`, 9999) + `"value"`
+	if got := arr.String(); got != expected {
+		t.Fatalf("Expected %d bytes, got %d", len(expected), len(got))
+	}
+	if got := arr.Len(); got != len(expected) {
+		t.Errorf("Expected length %d, got %d", len(expected), got)
+	}
+
+	arr.Close()
+	result, err := arr.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+	if string(result) != expected+"]" {
+		t.Errorf("Build after String lost content: got %d bytes", len(result))
+	}
+}
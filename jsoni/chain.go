@@ -0,0 +1,229 @@
+package jsoni
+
+import "github.com/mailru/easyjson/jwriter"
+
+// ObjectChain is a fluent surface over ObjectWriter: every field method returns the chain,
+// so an object and its nested objects and arrays can be written in a single expression.
+type ObjectChain struct {
+	writer  ObjectWriter
+	object  *ObjectChain // parent object, returned by End
+	array   *ArrayChain  // parent array, returned by EndItem
+	objects *ObjectChain // reused by Obj
+	arrays  *ArrayChain  // reused by Arr
+}
+
+// NewObjectChain creates a new ObjectChain given an optional writer from its parent node.
+func NewObjectChain(writer *jwriter.Writer) *ObjectChain {
+	return &ObjectChain{writer: NewObjectWriter(writer)}
+}
+
+// Open starts the JSON object by writing '{'.
+func (c *ObjectChain) Open() *ObjectChain {
+	c.writer.Open()
+	return c
+}
+
+// Obj adds a nested object field, opens it and returns its chain. Call End to get back here.
+// The returned chain is reused by later calls to Obj and must not be retained.
+func (c *ObjectChain) Obj(name string) *ObjectChain {
+	if c.objects == nil {
+		c.objects = &ObjectChain{object: c}
+	}
+
+	obj := c.objects
+	obj.writer.reuse(c.writer.ObjectField(name))
+	obj.writer.Open()
+	return obj
+}
+
+// Arr adds a nested array field, opens it and returns its chain. Call End to get back here.
+// The returned chain is reused by later calls to Arr and must not be retained.
+func (c *ObjectChain) Arr(name string) *ArrayChain {
+	if c.arrays == nil {
+		c.arrays = &ArrayChain{object: c}
+	}
+
+	arr := c.arrays
+	arr.writer.reuse(c.writer.ArrayField(name))
+	arr.writer.Open()
+	return arr
+}
+
+// Str adds a string field to the object.
+func (c *ObjectChain) Str(name, value string) *ObjectChain {
+	c.writer.StringField(name, value)
+	return c
+}
+
+// Num adds a number field to the object.
+func (c *ObjectChain) Num(name, value string) *ObjectChain {
+	c.writer.NumberField(name, value)
+	return c
+}
+
+// Int adds an integer field to the object.
+func (c *ObjectChain) Int(name string, value int64) *ObjectChain {
+	c.writer.IntegerField(name, value)
+	return c
+}
+
+// Float adds a float field to the object.
+func (c *ObjectChain) Float(name string, value float64) *ObjectChain {
+	c.writer.FloatField(name, value)
+	return c
+}
+
+// Bool adds a boolean field to the object.
+func (c *ObjectChain) Bool(name string, value bool) *ObjectChain {
+	c.writer.BooleanField(name, value)
+	return c
+}
+
+// Null adds a JSON null field to the object.
+func (c *ObjectChain) Null(name string) *ObjectChain {
+	c.writer.NullField(name)
+	return c
+}
+
+// Any adds a field of any type, automatically detecting its JSON representation.
+func (c *ObjectChain) Any(name string, value any) *ObjectChain {
+	c.writer.AnyField(name, value)
+	return c
+}
+
+// End finishes the JSON object by writing '}' and returns the parent object chain,
+// or nil if the object is a root or an array value.
+func (c *ObjectChain) End() *ObjectChain {
+	c.writer.Close()
+	return c.object
+}
+
+// EndItem finishes the JSON object by writing '}' and returns the parent array chain,
+// or nil if the object is a root or an object field.
+func (c *ObjectChain) EndItem() *ArrayChain {
+	c.writer.Close()
+	return c.array
+}
+
+// Writer returns the underlying ObjectWriter, for calls the chain does not offer.
+func (c *ObjectChain) Writer() *ObjectWriter {
+	return &c.writer
+}
+
+// BuildBytes returns the resulting JSON bytes.
+func (c *ObjectChain) BuildBytes() ([]byte, error) {
+	return c.writer.BuildBytes()
+}
+
+// ArrayChain is a fluent surface over ArrayWriter: every value method returns the chain,
+// so an array and its nested objects and arrays can be written in a single expression.
+type ArrayChain struct {
+	writer  ArrayWriter
+	object  *ObjectChain // parent object, returned by End
+	array   *ArrayChain  // parent array, returned by EndItem
+	objects *ObjectChain // reused by Obj
+	arrays  *ArrayChain  // reused by Arr
+}
+
+// NewArrayChain creates a new ArrayChain given an optional writer from its parent node.
+func NewArrayChain(writer *jwriter.Writer) *ArrayChain {
+	return &ArrayChain{writer: NewArrayWriter(writer)}
+}
+
+// Open starts the JSON array by writing '['.
+func (c *ArrayChain) Open() *ArrayChain {
+	c.writer.Open()
+	return c
+}
+
+// Obj appends a new object, opens it and returns its chain. Call EndItem to get back here.
+// The returned chain is reused by later calls to Obj and must not be retained.
+func (c *ArrayChain) Obj() *ObjectChain {
+	if c.objects == nil {
+		c.objects = &ObjectChain{array: c}
+	}
+
+	obj := c.objects
+	obj.writer.reuse(c.writer.ObjectValue())
+	obj.writer.Open()
+	return obj
+}
+
+// Arr appends a new nested array, opens it and returns its chain. Call EndItem to get back here.
+// The returned chain is reused by later calls to Arr and must not be retained.
+func (c *ArrayChain) Arr() *ArrayChain {
+	if c.arrays == nil {
+		c.arrays = &ArrayChain{array: c}
+	}
+
+	arr := c.arrays
+	arr.writer.reuse(c.writer.ArrayValue())
+	arr.writer.Open()
+	return arr
+}
+
+// Str appends a string value to the array.
+func (c *ArrayChain) Str(value string) *ArrayChain {
+	c.writer.StringValue(value)
+	return c
+}
+
+// Num appends a number value to the array.
+func (c *ArrayChain) Num(value string) *ArrayChain {
+	c.writer.NumberValue(value)
+	return c
+}
+
+// Int appends an integer value to the array.
+func (c *ArrayChain) Int(value int64) *ArrayChain {
+	c.writer.IntegerValue(value)
+	return c
+}
+
+// Float appends a float value to the array.
+func (c *ArrayChain) Float(value float64) *ArrayChain {
+	c.writer.FloatValue(value)
+	return c
+}
+
+// Bool appends a boolean value to the array.
+func (c *ArrayChain) Bool(value bool) *ArrayChain {
+	c.writer.BooleanValue(value)
+	return c
+}
+
+// Null appends a JSON null to the array.
+func (c *ArrayChain) Null() *ArrayChain {
+	c.writer.NullValue()
+	return c
+}
+
+// Any appends a value of any type, automatically detecting its JSON representation.
+func (c *ArrayChain) Any(value any) *ArrayChain {
+	c.writer.AnyValue(value)
+	return c
+}
+
+// End finishes the JSON array by writing ']' and returns the parent object chain,
+// or nil if the array is a root or an array value.
+func (c *ArrayChain) End() *ObjectChain {
+	c.writer.Close()
+	return c.object
+}
+
+// EndItem finishes the JSON array by writing ']' and returns the parent array chain,
+// or nil if the array is a root or an object field.
+func (c *ArrayChain) EndItem() *ArrayChain {
+	c.writer.Close()
+	return c.array
+}
+
+// Writer returns the underlying ArrayWriter, for calls the chain does not offer.
+func (c *ArrayChain) Writer() *ArrayWriter {
+	return &c.writer
+}
+
+// BuildBytes returns the resulting JSON bytes.
+func (c *ArrayChain) BuildBytes() ([]byte, error) {
+	return c.writer.BuildBytes()
+}
@@ -0,0 +1,85 @@
+package jsoni
+
+import "testing"
+
+func TestObjectChain(t *testing.T) {
+	c := NewObjectChain(nil)
+	c.Open().
+		Str("a", "x").
+		Int("b", 1).
+		Obj("c").
+		Bool("d", true).
+		Arr("e").Int(1).Obj().Null("f").EndItem().Arr().Str("g").EndItem().End().
+		End().
+		Float("h", 1.5).
+		Num("i", "1e3").
+		Any("j", []int{1, 2}).
+		Null("k").
+		End()
+
+	result, err := c.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `{"a":"x","b":1,"c":{"d":true,"e":[1,{"f":null},["g"]]},"h":1.5,"i":1e3,"j":[1,2],"k":null}`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
+
+func TestObjectChain_RootEnd(t *testing.T) {
+	c := NewObjectChain(nil)
+	if parent := c.Open().End(); parent != nil {
+		t.Errorf("Expected nil parent for a root object, got %v", parent)
+	}
+	if parent := NewObjectChain(nil).Open().EndItem(); parent != nil {
+		t.Errorf("Expected nil parent array for a root object, got %v", parent)
+	}
+}
+
+func TestArrayChain(t *testing.T) {
+	c := NewArrayChain(nil)
+	c.Open().
+		Str("a").
+		Int(1).
+		Float(2.5).
+		Bool(false).
+		Null().
+		Num("3").
+		Any("any").
+		Obj().Str("k", "v").Obj("o").End().EndItem().
+		Arr().Arr().EndItem().EndItem().
+		EndItem()
+
+	result, err := c.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `["a",1,2.5,false,null,3,"any",{"k":"v","o":{}},[[]]]`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
+
+func TestChain_Writer(t *testing.T) {
+	c := NewObjectChain(nil)
+	c.Open().Str("a", "x")
+	c.Writer().Object("nested", func(obj *ObjectWriter) {
+		obj.IntegerField("n", 1)
+	})
+	list := c.Arr("list")
+	list.Writer().StringValue("y")
+	list.End().End()
+
+	result, err := c.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `{"a":"x","nested":{"n":1},"list":["y"]}`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
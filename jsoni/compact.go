@@ -0,0 +1,59 @@
+package jsoni
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// errInvalidJSON is reported for marshaler output that is not valid JSON.
+var errInvalidJSON = errors.New("marshaler returned invalid JSON")
+
+// writeCompact writes data, the output of a marshaler, without insignificant whitespace,
+// escaping HTML characters in strings like encoding/json does unless the writer disables it.
+func writeCompact(writer *jwriter.Writer, data []byte) error {
+	if !json.Valid(data) {
+		return errInvalidJSON
+	}
+
+	escapeHTML := !writer.NoEscapeHTML
+	inString := false
+	start := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case c == '\\':
+				i++
+			case c == '"':
+				inString = false
+			case escapeHTML && (c == '<' || c == '>' || c == '&'):
+				writer.Buffer.AppendBytes(data[start:i])
+				writer.RawString(`\u00`)
+				writer.RawByte(hex[c>>4])
+				writer.RawByte(hex[c&0xf])
+				start = i + 1
+			case escapeHTML && c == 0xe2 && i+2 < len(data) && data[i+1] == 0x80 && data[i+2]&^1 == 0xa8:
+				// U+2028 and U+2029 are valid in JSON strings but not in JavaScript ones.
+				writer.Buffer.AppendBytes(data[start:i])
+				writer.RawString(`\u202`)
+				writer.RawByte(hex[data[i+2]&0xf])
+				i += 2
+				start = i + 1
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case ' ', '\t', '\n', '\r':
+			writer.Buffer.AppendBytes(data[start:i])
+			start = i + 1
+		}
+	}
+	writer.Buffer.AppendBytes(data[start:])
+	return nil
+}
+
+const hex = "0123456789abcdef"
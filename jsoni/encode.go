@@ -0,0 +1,535 @@
+package jsoni
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/binadel/jsonw/internal/typefields"
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// encoderFunc writes a value of one type, located in the document by path and depth.
+type encoderFunc func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error
+
+// encoders caches the encoding plan of every type seen so far, keyed by reflect.Type.
+var encoders sync.Map
+
+var (
+	objectMarshalerType = reflect.TypeOf((*ObjectMarshaler)(nil)).Elem()
+	valueMarshalerType  = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
+	easyMarshalerType   = reflect.TypeOf((*easyjson.Marshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	numberType          = reflect.TypeOf(json.Number(""))
+)
+
+// writeReflect writes value by reflection, following the rules of encoding/json:
+// struct tags with omitempty and string options, embedded structs, sorted map keys,
+// base64 byte slices, and the marshaler interfaces recognized by encodeAny.
+func writeReflect(writer *jwriter.Writer, p *path, depth int, state encodeState, value any) error {
+	v := reflect.ValueOf(value)
+	return typeEncoder(v.Type())(writer, p, depth, state, v)
+}
+
+// startDetectingCyclesAfter is the number of nested pointers, maps and slices after which
+// their addresses are recorded to detect cycles, like encoding/json does.
+const startDetectingCyclesAfter = 1000
+
+// encodeState is passed down by value through the encoders to detect cycles.
+type encodeState struct {
+	ptrLevel uint
+	ptrSeen  map[any]struct{}
+}
+
+// enter returns the state for the elements of a pointer, map or slice, or an error if it is
+// already being written. Only values nested deeper than startDetectingCyclesAfter are checked.
+func (state encodeState) enter(v reflect.Value) (encodeState, error) {
+	state.ptrLevel++
+	if state.ptrLevel <= startDetectingCyclesAfter {
+		return state, nil
+	}
+
+	if state.ptrSeen == nil {
+		state.ptrSeen = make(map[any]struct{})
+	}
+	key := cycleKey(v)
+	if _, ok := state.ptrSeen[key]; ok {
+		return state, &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + v.Type().String()}
+	}
+	state.ptrSeen[key] = struct{}{}
+	return state, nil
+}
+
+// leave forgets a value recorded by the enter call that returned the state.
+func (state encodeState) leave(v reflect.Value) {
+	if state.ptrLevel > startDetectingCyclesAfter {
+		delete(state.ptrSeen, cycleKey(v))
+	}
+}
+
+// cycleKey identifies a pointer, map or slice. Slices also need their length, since
+// a slice may contain a shorter slice of the same array.
+func cycleKey(v reflect.Value) any {
+	if v.Kind() == reflect.Slice {
+		return struct {
+			ptr uintptr
+			len int
+		}{v.Pointer(), v.Len()}
+	}
+	return v.Pointer()
+}
+
+// typeEncoder returns the cached encoder of a type, building it on first use.
+func typeEncoder(t reflect.Type) encoderFunc {
+	if f, ok := encoders.Load(t); ok {
+		return f.(encoderFunc)
+	}
+
+	// Recursive types reach this point again while their encoder is being built,
+	// so an indirect encoder waiting for the real one is stored first.
+	var (
+		wg sync.WaitGroup
+		f  encoderFunc
+	)
+	wg.Add(1)
+	indirect := encoderFunc(func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		wg.Wait()
+		return f(writer, p, depth, state, v)
+	})
+	if actual, loaded := encoders.LoadOrStore(t, indirect); loaded {
+		return actual.(encoderFunc)
+	}
+
+	f = newTypeEncoder(t, true)
+	wg.Done()
+	encoders.Store(t, f)
+	return f
+}
+
+// isMarshaler reports whether the type writes itself through one of the interfaces handled by encodeAny.
+func isMarshaler(t reflect.Type) bool {
+	return t.Implements(objectMarshalerType) ||
+		t.Implements(valueMarshalerType) ||
+		t.Implements(easyMarshalerType) ||
+		t.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType)
+}
+
+func newTypeEncoder(t reflect.Type, allowAddr bool) encoderFunc {
+	// Marshalers with pointer receivers are used whenever the value is addressable.
+	if t.Kind() != reflect.Pointer && allowAddr && !isMarshaler(t) && isMarshaler(reflect.PointerTo(t)) {
+		return condAddrEncoder(addrMarshalerEncoder, newTypeEncoder(t, false))
+	}
+	if isMarshaler(t) {
+		return marshalerEncoder
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolEncoder
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intEncoder
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintEncoder
+	case reflect.Float32:
+		return float32Encoder
+	case reflect.Float64:
+		return float64Encoder
+	case reflect.String:
+		if t == numberType {
+			return numberEncoder
+		}
+		return stringEncoder
+	case reflect.Interface:
+		return interfaceEncoder
+	case reflect.Struct:
+		return newStructEncoder(t)
+	case reflect.Map:
+		return newMapEncoder(t)
+	case reflect.Slice:
+		return newSliceEncoder(t)
+	case reflect.Array:
+		return newArrayEncoder(t)
+	case reflect.Pointer:
+		return newPointerEncoder(t)
+	default:
+		return unsupportedTypeEncoder
+	}
+}
+
+func marshalerEncoder(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		writer.Raw(nullValue, nil)
+		return nil
+	}
+	return encodeAny(writer, p, depth, state, v.Interface())
+}
+
+func addrMarshalerEncoder(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+	return encodeAny(writer, p, depth, state, v.Addr().Interface())
+}
+
+// condAddrEncoder uses ifAddr for addressable values and otherwise for the rest.
+func condAddrEncoder(ifAddr, otherwise encoderFunc) encoderFunc {
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		if v.CanAddr() {
+			return ifAddr(writer, p, depth, state, v)
+		}
+		return otherwise(writer, p, depth, state, v)
+	}
+}
+
+func boolEncoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	writer.Bool(v.Bool())
+	return nil
+}
+
+func intEncoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	writer.Int64(v.Int())
+	return nil
+}
+
+func uintEncoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	writer.Uint64(v.Uint())
+	return nil
+}
+
+func float32Encoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	return writeFloat(writer, v.Float(), 32)
+}
+
+func float64Encoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	return writeFloat(writer, v.Float(), 64)
+}
+
+func stringEncoder(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	writer.String(v.String())
+	return nil
+}
+
+func numberEncoder(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+	return encodeAny(writer, p, depth, state, json.Number(v.String()))
+}
+
+func interfaceEncoder(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+	if v.IsNil() {
+		writer.Raw(nullValue, nil)
+		return nil
+	}
+	elem := v.Elem()
+	return typeEncoder(elem.Type())(writer, p, depth, state, elem)
+}
+
+func unsupportedTypeEncoder(_ *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+	return &json.UnsupportedTypeError{Type: v.Type()}
+}
+
+// newQuotedEncoder returns the encoder of a field with the string tag option,
+// which writes the value inside a JSON string.
+func newQuotedEncoder(t reflect.Type) encoderFunc {
+	if t.Kind() == reflect.Pointer {
+		elem := newQuotedEncoder(t.Elem())
+		return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+			if v.IsNil() {
+				writer.Raw(nullValue, nil)
+				return nil
+			}
+			return elem(writer, p, depth, state, v.Elem())
+		}
+	}
+
+	if t.Kind() == reflect.String && t != numberType {
+		return func(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+			var inner jwriter.Writer
+			inner.NoEscapeHTML = writer.NoEscapeHTML
+			inner.String(v.String())
+			writer.String(string(inner.Buffer.BuildBytes()))
+			return nil
+		}
+	}
+
+	enc := typeEncoder(t)
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		writer.RawByte(quote)
+		err := enc(writer, p, depth, state, v)
+		writer.RawByte(quote)
+		return err
+	}
+}
+
+// structField is a member of the encoding plan of a struct type.
+type structField struct {
+	name      string // the JSON key
+	raw       string // the key escaped for writing between quotes
+	index     []int  // the field index sequence, through embedded structs
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+	encode    encoderFunc
+}
+
+func newStructEncoder(t reflect.Type) encoderFunc {
+	fields := typeFields(t)
+	for i := range fields {
+		f := &fields[i]
+		if f.quoted {
+			f.encode = newQuotedEncoder(f.typ)
+		} else {
+			f.encode = typeEncoder(f.typ)
+		}
+	}
+
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		obj := ObjectWriter{writer: writer, path: p, depth: depth}
+		obj.Open()
+	next:
+		for i := range fields {
+			f := &fields[i]
+			fv := v
+			for _, index := range f.index {
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						continue next
+					}
+					fv = fv.Elem()
+				}
+				fv = fv.Field(index)
+			}
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+
+			start := obj.Mark()
+			memberPath, memberDepth := obj.rawMember(f.raw, f.name)
+			if err := f.encode(writer, memberPath, memberDepth, state, fv); err != nil {
+				obj.Rollback(start)
+				obj.fail(f.name, err)
+			}
+		}
+		obj.Close()
+		return nil
+	}
+}
+
+func newMapEncoder(t reflect.Type) encoderFunc {
+	switch t.Key().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !t.Key().Implements(textMarshalerType) {
+			return unsupportedTypeEncoder
+		}
+	}
+
+	elem := typeEncoder(t.Elem())
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			writer.Raw(nullValue, nil)
+			return nil
+		}
+
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{key, iter.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+
+		inner, err := state.enter(v)
+		if err != nil {
+			return err
+		}
+		obj := ObjectWriter{writer: writer, path: p, depth: depth}
+		obj.Open()
+		for _, e := range entries {
+			start := obj.Mark()
+			memberPath, memberDepth := obj.anyMember(e.key)
+			if err := elem(writer, memberPath, memberDepth, inner, e.value); err != nil {
+				obj.Rollback(start)
+				obj.fail(e.key, err)
+			}
+		}
+		obj.Close()
+		inner.leave(v)
+		return nil
+	}
+}
+
+// mapKey returns the JSON key of a map key, as encoding/json resolves it.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	default:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+}
+
+func newSliceEncoder(t reflect.Type) encoderFunc {
+	// Byte slices are written as base64 strings, unless their elements write themselves.
+	if t.Elem().Kind() == reflect.Uint8 && !isMarshaler(t.Elem()) && !isMarshaler(reflect.PointerTo(t.Elem())) {
+		return func(writer *jwriter.Writer, _ *path, _ int, _ encodeState, v reflect.Value) error {
+			if v.IsNil() {
+				writer.Raw(nullValue, nil)
+				return nil
+			}
+			writer.Base64Bytes(v.Bytes())
+			return nil
+		}
+	}
+
+	array := newArrayEncoder(t)
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			writer.Raw(nullValue, nil)
+			return nil
+		}
+		inner, err := state.enter(v)
+		if err != nil {
+			return err
+		}
+		err = array(writer, p, depth, inner, v)
+		inner.leave(v)
+		return err
+	}
+}
+
+func newArrayEncoder(t reflect.Type) encoderFunc {
+	elem := typeEncoder(t.Elem())
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for i, n := 0, v.Len(); i < n; i++ {
+			start := arr.Mark()
+			valuePath, valueDepth := arr.startValue()
+			if err := elem(writer, valuePath, valueDepth, state, v.Index(i)); err != nil {
+				arr.Rollback(start)
+				arr.fail(err)
+				continue
+			}
+			arr.index++
+		}
+		arr.Close()
+		return nil
+	}
+}
+
+func newPointerEncoder(t reflect.Type) encoderFunc {
+	elem := typeEncoder(t.Elem())
+	return func(writer *jwriter.Writer, p *path, depth int, state encodeState, v reflect.Value) error {
+		if v.IsNil() {
+			writer.Raw(nullValue, nil)
+			return nil
+		}
+		inner, err := state.enter(v)
+		if err != nil {
+			return err
+		}
+		err = elem(writer, p, depth, inner, v.Elem())
+		inner.leave(v)
+		return err
+	}
+}
+
+// isEmptyValue reports whether the value is left out by the omitempty tag option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// typeFields returns the fields encoding/json would write for the struct type,
+// resolving the names promoted from embedded structs with the same rules.
+func typeFields(t reflect.Type) []structField {
+	resolved := typefields.Resolve(t, structMembers)
+	fields := make([]structField, len(resolved))
+	for i, f := range resolved {
+		fields[i] = structField{
+			name:      f.Name,
+			raw:       escapeKey(f.Name),
+			index:     f.Index,
+			typ:       f.Type,
+			tagged:    f.Tagged,
+			omitEmpty: f.OmitEmpty,
+			quoted:    f.Quoted,
+		}
+	}
+	return fields
+}
+
+// structMembers lists the fields of a struct type for typefields.Resolve.
+func structMembers(t reflect.Type) []typefields.Member[reflect.Type] {
+	members := make([]typefields.Member[reflect.Type], t.NumField())
+	for i := range members {
+		sf := t.Field(i)
+		elem := sf.Type
+		if elem.Name() == "" && elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+
+		scalar := false
+		switch elem.Kind() {
+		case reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64,
+			reflect.String:
+			scalar = true
+		}
+
+		members[i] = typefields.Member[reflect.Type]{
+			Name:     sf.Name,
+			Tag:      sf.Tag.Get("json"),
+			Exported: sf.IsExported(),
+			Embedded: sf.Anonymous,
+			Type:     sf.Type,
+			Elem:     elem,
+			Struct:   elem.Kind() == reflect.Struct,
+			Scalar:   scalar,
+		}
+	}
+	return members
+}
+
+// escapeKey returns the key as it must appear between quotes in the output.
+func escapeKey(key string) string {
+	var w jwriter.Writer
+	w.String(key)
+	escaped := w.Buffer.BuildBytes()
+	return string(escaped[1 : len(escaped)-1])
+}
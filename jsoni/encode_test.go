@@ -0,0 +1,199 @@
+package jsoni
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type reflectBase struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Shadow  string
+	private int
+}
+
+type reflectOther struct {
+	Shadow string
+	Extra  bool `json:"extra,omitempty"`
+}
+
+type reflectTagged struct {
+	Shadow string `json:"Shadow"`
+}
+
+type reflectEmbedded struct {
+	reflectBase
+	*reflectOther
+	reflectTagged
+
+	Name      string            `json:"display"`
+	Skipped   string            `json:"-"`
+	Dash      string            `json:"-,"`
+	Count     int64             `json:"count,string"`
+	Ratio     float64           `json:"ratio,string"`
+	Enabled   bool              `json:",string"`
+	Quoted    string            `json:"quoted,string"`
+	PtrQuoted *int              `json:"ptr_quoted,string"`
+	Empty     string            `json:"empty,omitempty"`
+	Zero      int               `json:"zero,omitempty"`
+	NilMap    map[string]int    `json:"nil_map,omitempty"`
+	Data      []byte            `json:"data"`
+	NilData   []byte            `json:"nil_data"`
+	Fixed     [2]uint8          `json:"fixed"`
+	IntKeys   map[int]string    `json:"int_keys"`
+	TextKeys  map[level]int     `json:"-"`
+	Nested    *reflectEmbedded  `json:"nested,omitempty"`
+	Any       any               `json:"any"`
+	Stamp     time.Time         `json:"stamp"`
+	Number    json.Number       `json:"number"`
+	Raw       json.RawMessage   `json:"raw"`
+	Unicode   string            `json:"é<>"`
+	Structs   []reflectTagged   `json:"structs"`
+	Pointers  map[string]*int64 `json:"pointers"`
+}
+
+type reflectConflict struct {
+	reflectBase
+	reflectOther
+}
+
+type reflectNode struct {
+	Value    int            `json:"value"`
+	Children []*reflectNode `json:"children,omitempty"`
+}
+
+type addrMarshaler struct {
+	Value string
+}
+
+func (a *addrMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal("addr:" + a.Value)
+}
+
+type reflectAddr struct {
+	Field addrMarshaler  `json:"field"`
+	Ptr   *addrMarshaler `json:"ptr"`
+}
+
+func TestWriteReflect_MatchesEncodingJSON(t *testing.T) {
+	seven := 7
+	big := int64(1 << 60)
+
+	values := []any{
+		reflectEmbedded{
+			reflectBase:   reflectBase{ID: 1, Name: "base", Shadow: "shadowed", private: 3},
+			reflectTagged: reflectTagged{Shadow: "tagged"},
+			Name:          "display <name>",
+			Skipped:       "skipped",
+			Dash:          "dash",
+			Count:         -12,
+			Ratio:         0.5,
+			Enabled:       true,
+			Quoted:        `say "hi" <now>`,
+			PtrQuoted:     &seven,
+			Data:          []byte("hello, world"),
+			Fixed:         [2]uint8{1, 2},
+			IntKeys:       map[int]string{10: "ten", -1: "minus", 2: "two"},
+			Nested:        &reflectEmbedded{Name: "child"},
+			Any:           map[string]any{"k": []any{1, "v"}},
+			Stamp:         time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+			Number:        "3.14",
+			Raw:           json.RawMessage(`{"a": 1}`),
+			Unicode:       "unicode",
+			Structs:       []reflectTagged{{"a"}, {"b"}},
+			Pointers:      map[string]*int64{"set": &big, "unset": nil},
+		},
+		reflectEmbedded{reflectOther: &reflectOther{Shadow: "other", Extra: true}},
+		reflectConflict{reflectBase{Shadow: "a"}, reflectOther{Shadow: "b"}},
+		&reflectNode{Value: 1, Children: []*reflectNode{{Value: 2}, {Value: 3, Children: []*reflectNode{{Value: 4}}}}},
+		&reflectAddr{Field: addrMarshaler{"x"}, Ptr: &addrMarshaler{"y"}},
+		reflectAddr{Field: addrMarshaler{"x"}},
+		map[string]int(nil),
+		[]reflectTagged(nil),
+		[0]int{},
+		struct{}{},
+		struct{ Any any }{time.Second},
+		struct{ Err any }{errors.New("x")},
+		map[string]any{"d": time.Second, "e": errors.New("x")},
+		[]any{time.Second, []any{errors.New("x")}},
+	}
+
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+
+		arr := NewArrayWriter(nil)
+		arr.AnyValue(value)
+		result, err := arr.BuildBytes()
+		if err != nil {
+			t.Fatalf("BuildBytes failed: %v", err)
+		}
+		if string(result) != string(expected) {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+	}
+}
+
+func TestWriteReflect_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		path  string
+	}{
+		{"unsupported float in struct", struct {
+			List []struct{ F float64 } `json:"list"`
+		}{List: []struct{ F float64 }{{1}, {math.Inf(1)}}}, "/value/list/1/F"},
+		{"unsupported type", struct{ Fn func() }{}, "/value/Fn"},
+		{"unsupported map key", map[float64]int{1: 1}, "/value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.TrackPath()
+			obj.Open()
+			obj.AnyField("value", tt.value)
+			obj.Close()
+
+			var e *Error
+			if !errors.As(obj.Err(), &e) {
+				t.Fatalf("Expected *Error, got %v", obj.Err())
+			}
+			if e.Path != tt.path {
+				t.Errorf("Expected path %q, got %q", tt.path, e.Path)
+			}
+		})
+	}
+}
+
+type reflectCycle struct {
+	Next *reflectCycle `json:"next"`
+}
+
+func TestWriteReflect_Cycles(t *testing.T) {
+	node := &reflectCycle{}
+	node.Next = node
+	slice := []any{nil}
+	slice[0] = slice
+	object := map[string]any{}
+	object["self"] = object
+
+	for _, value := range []any{node, slice, object, struct{ Any any }{node}} {
+		arr := NewArrayWriter(nil)
+		arr.AnyValue(value)
+		_, err := arr.BuildBytes()
+
+		var unsupported *json.UnsupportedValueError
+		if !errors.As(err, &unsupported) {
+			t.Fatalf("Expected *json.UnsupportedValueError, got %v", err)
+		}
+		if _, expected := json.Marshal(value); unsupported.Str != expected.(*json.UnsupportedValueError).Str {
+			t.Errorf("Expected %q, got %q", expected.(*json.UnsupportedValueError).Str, unsupported.Str)
+		}
+	}
+}
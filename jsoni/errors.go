@@ -0,0 +1,40 @@
+package jsoni
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrUnsupportedFloat is reported for NaN and infinite floats, which JSON cannot represent.
+	ErrUnsupportedFloat = errors.New("unsupported float value")
+	// ErrInvalidNumber is reported for number literals that are not valid JSON numbers.
+	ErrInvalidNumber = errors.New("invalid number literal")
+)
+
+// Error is the error reported by the writers, recording where in the document it happened.
+// The offending member or value, including a partly written one, is left out of the output,
+// and the error is returned by BuildBytes.
+type Error struct {
+	// Path is the JSON Pointer of the offending member or value.
+	// It is empty unless path tracking was turned on with TrackPath.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return "jsoni: " + e.Err.Error()
+	}
+	return "jsoni: " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// validFloat reports whether the float can be written as a JSON number.
+func validFloat(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
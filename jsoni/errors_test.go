@@ -0,0 +1,66 @@
+package jsoni
+
+import (
+	"math"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		err      *Error
+		expected string
+	}{
+		{&Error{Err: ErrInvalidNumber}, "jsoni: invalid number literal"},
+		{&Error{Path: "/a/0", Err: ErrUnsupportedFloat}, "jsoni: /a/0: unsupported float value"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestError_LeavesOutValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(obj *ObjectWriter)
+		expected string
+	}{
+		{"any field", func(obj *ObjectWriter) {
+			obj.AnyField("b", math.NaN())
+		}, `{"a":"x"`},
+		{"map member", func(obj *ObjectWriter) {
+			obj.AnyField("b", map[string]any{"x": 1, "y": math.Inf(1)})
+		}, `{"a":"x","b":{"x":1}`},
+		{"struct field", func(obj *ObjectWriter) {
+			obj.AnyField("b", struct {
+				B int
+				A float64
+			}{1, math.NaN()})
+		}, `{"a":"x","b":{"B":1}`},
+		{"any value", func(obj *ObjectWriter) {
+			obj.Array("b", func(arr *ArrayWriter) {
+				arr.AnyValue(1)
+				arr.AnyValue(math.NaN())
+			})
+		}, `{"a":"x","b":[1]`},
+		{"slice element", func(obj *ObjectWriter) {
+			obj.AnyField("b", []float32{2, float32(math.Inf(-1))})
+		}, `{"a":"x","b":[2]`},
+	}
+
+	for _, tt := range tests {
+		obj := NewObjectWriter(nil)
+		obj.Open()
+		obj.StringField("a", "x")
+		tt.write(&obj)
+
+		if got := obj.String(); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+		if obj.Err() == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
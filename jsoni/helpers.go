@@ -1,12 +1,67 @@
 package jsoni
 
 import (
+	"encoding"
 	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
 
+	"github.com/binadel/jsonw/internal/number"
+	"github.com/binadel/jsonw/internal/sorted"
+	"github.com/mailru/easyjson"
 	"github.com/mailru/easyjson/jwriter"
 )
 
-func writeAny(writer *jwriter.Writer, value any) {
+// writeAny writes the value of AnyField and AnyValue, returning an error instead of writing it
+// if it cannot be represented. The path and depth locate the value in the document for the
+// writers of nested marshalers.
+//
+// It writes the value like encodeAny, except that errors and fmt.Stringers that don't marshal
+// themselves are written as strings. Values nested in the value are written like encoding/json
+// writes them, without this default.
+func writeAny(writer *jwriter.Writer, p *path, depth int, value any) error {
+	switch v := value.(type) {
+	case error:
+		if !marshalsItself(v) {
+			if isNilPointer(v) {
+				writer.Raw(nullValue, nil)
+				return nil
+			}
+			writer.String(v.Error())
+			return nil
+		}
+	case fmt.Stringer:
+		if !marshalsItself(v) {
+			if isNilPointer(v) {
+				writer.Raw(nullValue, nil)
+				return nil
+			}
+			writer.String(v.String())
+			return nil
+		}
+	}
+	return encodeAny(writer, p, depth, encodeState{}, value)
+}
+
+// marshalsItself reports whether encodeAny writes value with one of the interfaces it recognizes.
+func marshalsItself(value any) bool {
+	switch value.(type) {
+	case json.Number, json.RawMessage, ObjectMarshaler, ValueMarshaler, easyjson.Marshaler, json.Marshaler, encoding.TextMarshaler:
+		return true
+	}
+	return false
+}
+
+// encodeAny writes value like encoding/json writes it, returning an error instead of writing it
+// if it cannot be represented. The state detects cycles through the values it is nested in.
+//
+// Besides primitives, pointers to primitives and common slices and maps, which are written
+// directly, it recognizes, in order of precedence: json.Number, json.RawMessage, ObjectMarshaler,
+// ValueMarshaler, easyjson.Marshaler, json.Marshaler and encoding.TextMarshaler.
+// Anything else is written by reflection with a cached per-type plan, see writeReflect.
+func encodeAny(writer *jwriter.Writer, p *path, depth int, state encodeState, value any) error {
 	switch v := value.(type) {
 	case string:
 		writer.String(v)
@@ -31,14 +86,280 @@ func writeAny(writer *jwriter.Writer, value any) {
 	case uint64:
 		writer.Uint64(v)
 	case float32:
-		writer.Float32(v)
+		return writeFloat(writer, float64(v), 32)
 	case float64:
-		writer.Float64(v)
+		return writeFloat(writer, v, 64)
 	case bool:
 		writer.Bool(v)
 	case nil:
 		writer.Raw(nullValue, nil)
+	case *string:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.String(*v)
+	case *int:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Int(*v)
+	case *int32:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Int32(*v)
+	case *int64:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Int64(*v)
+	case *uint:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Uint(*v)
+	case *uint32:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Uint32(*v)
+	case *uint64:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Uint64(*v)
+	case *float32:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		return writeFloat(writer, float64(*v), 32)
+	case *float64:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		return writeFloat(writer, *v, 64)
+	case *bool:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		writer.Bool(*v)
+	case []string:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.StringValue(item)
+		}
+		arr.Close()
+	case []int:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.IntegerValue(int64(item))
+		}
+		arr.Close()
+	case []int64:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.IntegerValue(item)
+		}
+		arr.Close()
+	case []float64:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.anyFloat(item)
+		}
+		arr.Close()
+	case []bool:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.BooleanValue(item)
+		}
+		arr.Close()
+	case []any:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		inner, err := state.enter(reflect.ValueOf(v))
+		if err != nil {
+			return err
+		}
+		arr := ArrayWriter{writer: writer, path: p, depth: depth}
+		arr.Open()
+		for _, item := range v {
+			arr.encodeValue(inner, item)
+		}
+		arr.Close()
+		inner.leave(reflect.ValueOf(v))
+	case map[string]string:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		obj := ObjectWriter{writer: writer, path: p, depth: depth}
+		obj.Open()
+		for _, key := range sorted.Keys(v) {
+			obj.anyMember(key)
+			writer.String(v[key])
+		}
+		obj.Close()
+	case map[string]any:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		inner, err := state.enter(reflect.ValueOf(v))
+		if err != nil {
+			return err
+		}
+		obj := ObjectWriter{writer: writer, path: p, depth: depth}
+		obj.Open()
+		for _, key := range sorted.Keys(v) {
+			start := obj.Mark()
+			memberPath, memberDepth := obj.anyMember(key)
+			if err := encodeAny(writer, memberPath, memberDepth, inner, v[key]); err != nil {
+				obj.Rollback(start)
+				obj.fail(key, err)
+			}
+		}
+		obj.Close()
+		inner.leave(reflect.ValueOf(v))
+	case json.Number:
+		if v == "" {
+			v = "0"
+		}
+		if !number.Valid(string(v)) {
+			return ErrInvalidNumber
+		}
+		writer.RawString(string(v))
+	case json.RawMessage:
+		if v == nil {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		return writeCompact(writer, v)
+	case ObjectMarshaler:
+		if isNilPointer(v) {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		obj := ObjectWriter{writer: writer, path: p, depth: depth}
+		obj.Open()
+		v.WriteJSONObject(&obj)
+		obj.Close()
+	case ValueMarshaler:
+		if isNilPointer(v) {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		arr := valueWriter(writer, p, depth)
+		v.WriteJSONValue(&arr)
+	case easyjson.Marshaler:
+		if isNilPointer(v) {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		return writeEasyJSON(writer, v)
+	case json.Marshaler:
+		if isNilPointer(v) {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		return writeCompact(writer, data)
+	case encoding.TextMarshaler:
+		if isNilPointer(v) {
+			writer.Raw(nullValue, nil)
+			break
+		}
+		text, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		writer.String(string(text))
 	default:
-		writer.Raw(json.Marshal(value))
+		return writeReflect(writer, p, depth, state, value)
+	}
+	return nil
+}
+
+// isNilPointer reports whether value holds a nil pointer, on which methods would usually panic.
+func isNilPointer(value any) bool {
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// writeEasyJSON lets the marshaler write itself directly, returning the error it records
+// so that it can be reported with the location of the value.
+func writeEasyJSON(writer *jwriter.Writer, value easyjson.Marshaler) error {
+	prev := writer.Error
+	value.MarshalEasyJSON(writer)
+	if prev != nil {
+		return nil
+	}
+	err := writer.Error
+	writer.Error = nil
+	return err
+}
+
+// writeFloat writes a float with the given bit size, formatted like encoding/json formats it.
+func writeFloat(writer *jwriter.Writer, value float64, bits int) error {
+	if !validFloat(value) {
+		return ErrUnsupportedFloat
+	}
+
+	format := byte('f')
+	if abs := math.Abs(value); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	writer.Buffer.EnsureSpace(32)
+	buf := strconv.AppendFloat(writer.Buffer.Buf, value, format, -1, bits)
+	if format == 'e' {
+		// Shorten a two-digit negative exponent like e-09 to e-9.
+		if n := len(buf); n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
 	}
+	writer.Buffer.Buf = buf
+	return nil
 }
@@ -0,0 +1,272 @@
+package jsoni
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+type easyValue struct {
+	Name string
+}
+
+func (v easyValue) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString(`{"name":`)
+	w.String(v.Name)
+	w.RawByte('}')
+}
+
+type easyFailure struct{}
+
+func (easyFailure) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Raw(nil, errors.New("easy failure"))
+}
+
+type spacedJSON struct{}
+
+func (*spacedJSON) MarshalJSON() ([]byte, error) {
+	return []byte("{ \"html\" : \"<a & b>\",\n \"sep\": \" \", \"list\": [ 1, 2 ] }"), nil
+}
+
+type brokenJSON struct{}
+
+func (brokenJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`{"unterminated":`), nil
+}
+
+type level int
+
+func (l level) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+func TestWriteAny_Marshalers(t *testing.T) {
+	stamp := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "json number",
+			value:    json.Number("12.50"),
+			expected: `12.50`,
+		},
+		{
+			name:     "empty json number",
+			value:    json.Number(""),
+			expected: `0`,
+		},
+		{
+			name:     "raw message is compacted",
+			value:    json.RawMessage("[ 1,\n\t2 ]"),
+			expected: `[1,2]`,
+		},
+		{
+			name:     "nil raw message",
+			value:    json.RawMessage(nil),
+			expected: `null`,
+		},
+		{
+			name:     "easyjson marshaler",
+			value:    easyValue{Name: "<John>"},
+			expected: `{"name":"\u003cJohn\u003e"}`,
+		},
+		{
+			name:     "json marshaler",
+			value:    stamp,
+			expected: `"2024-05-06T07:08:09Z"`,
+		},
+		{
+			name:     "json marshaler output is compacted and escaped",
+			value:    &spacedJSON{},
+			expected: `{"html":"\u003ca \u0026 b\u003e","sep":"\u2028","list":[1,2]}`,
+		},
+		{
+			name:     "nil json marshaler",
+			value:    (*spacedJSON)(nil),
+			expected: `null`,
+		},
+		{
+			name:     "text marshaler",
+			value:    net.IPv4(10, 0, 0, 1),
+			expected: `"10.0.0.1"`,
+		},
+		{
+			name:     "error",
+			value:    errors.New("boom"),
+			expected: `"boom"`,
+		},
+		{
+			name:     "stringer",
+			value:    level(1),
+			expected: `"high"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := NewArrayWriter(nil)
+			arr.AnyValue(tt.value)
+
+			result, err := arr.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWriteAny_MarshalersMatchEncodingJSON(t *testing.T) {
+	values := []any{
+		json.Number("1e3"),
+		json.RawMessage(` { "a" : [ "<>" ] } `),
+		time.Date(2024, 5, 6, 7, 8, 9, 123, time.FixedZone("X", 3600)),
+		&spacedJSON{},
+		(*spacedJSON)(nil),
+		net.IPv4(192, 168, 0, 1),
+	}
+
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+
+		arr := NewArrayWriter(nil)
+		arr.AnyValue(value)
+		result, err := arr.BuildBytes()
+		if err != nil {
+			t.Fatalf("BuildBytes failed: %v", err)
+		}
+		if string(result) != string(expected) {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+	}
+}
+
+func TestWriteAny_MarshalerErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		err   error
+	}{
+		{"invalid json number", json.Number("1.2.3"), ErrInvalidNumber},
+		{"invalid raw message", json.RawMessage(`{`), errInvalidJSON},
+		{"invalid json marshaler output", brokenJSON{}, errInvalidJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.TrackPath()
+			obj.Open()
+			obj.AnyField("value", tt.value)
+			obj.Close()
+
+			var e *Error
+			if !errors.As(obj.Err(), &e) {
+				t.Fatalf("Expected *Error, got %v", obj.Err())
+			}
+			if e.Path != "/value" {
+				t.Errorf("Expected path %q, got %q", "/value", e.Path)
+			}
+			if !errors.Is(e, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, e.Err)
+			}
+		})
+	}
+
+	t.Run("easyjson marshaler error", func(t *testing.T) {
+		arr := NewArrayWriter(nil)
+		arr.TrackPath()
+		arr.Open()
+		arr.AnyValue(easyFailure{})
+		arr.Close()
+
+		var e *Error
+		if !errors.As(arr.Err(), &e) {
+			t.Fatalf("Expected *Error, got %v", arr.Err())
+		}
+		if e.Path != "/0" || e.Err.Error() != "easy failure" {
+			t.Errorf("Expected easy failure at /0, got %v", e)
+		}
+	})
+}
+
+func TestWriteAny_CompositesMatchEncodingJSON(t *testing.T) {
+	text := "<b>&</b>"
+	number := int64(-42)
+	ratio := 1e-7
+	flag := true
+
+	values := []any{
+		[]string{"a", "<b>", ""},
+		[]string{},
+		[]string(nil),
+		[]int{1, -2, 3},
+		[]int64{9007199254740993, -1},
+		[]float64{0, 1.5, 123456789, 1e21, 1e-7, -0.000001},
+		[]bool{true, false},
+		[]any{"x", 1, 2.5, true, nil, []any{}, map[string]any{}},
+		map[string]string{"b": "2", "a": "1", "<k>": "v", "é": "e"},
+		map[string]string(nil),
+		map[string]any{
+			"z":      nil,
+			"nested": map[string]any{"list": []any{1e100, float32(3.4e38), "s"}},
+			"a":      []string{"x"},
+		},
+		&text,
+		(*string)(nil),
+		&number,
+		&ratio,
+		&flag,
+		(*bool)(nil),
+		float32(1e-7),
+		float64(123456789),
+	}
+
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+
+		arr := NewArrayWriter(nil)
+		arr.AnyValue(value)
+		result, err := arr.BuildBytes()
+		if err != nil {
+			t.Fatalf("BuildBytes failed: %v", err)
+		}
+		if string(result) != string(expected) {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+	}
+}
+
+func TestWriteAny_CompositeErrors(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.TrackPath()
+	obj.Open()
+	obj.AnyField("data", map[string]any{
+		"a/b": []any{1, map[string]any{"f": []float64{1, math.NaN()}}},
+	})
+	obj.Close()
+
+	var e *Error
+	if !errors.As(obj.Err(), &e) {
+		t.Fatalf("Expected *Error, got %v", obj.Err())
+	}
+	if e.Path != "/data/a~1b/1/f/1" {
+		t.Errorf("Expected path %q, got %q", "/data/a~1b/1/f/1", e.Path)
+	}
+}
@@ -0,0 +1,22 @@
+package jsoni
+
+import (
+	"github.com/binadel/jsonw/internal/hooks"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func init() {
+	hooks.Encode = func(writer *jwriter.Writer, value any) {
+		w := ArrayWriter{writer: writer}
+		w.encodeValue(encodeState{}, value)
+	}
+	hooks.Writer = func(w any) *jwriter.Writer {
+		switch w := w.(type) {
+		case *ObjectWriter:
+			return w.writer
+		case *ArrayWriter:
+			return w.writer
+		}
+		panic("jsoni: not a writer")
+	}
+}
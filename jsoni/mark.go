@@ -0,0 +1,8 @@
+package jsoni
+
+// Mark is a position in the output that a writer can later roll back to.
+type Mark struct {
+	size       int
+	needsComma bool
+	index      int
+}
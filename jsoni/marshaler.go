@@ -0,0 +1,29 @@
+package jsoni
+
+// ObjectMarshaler is implemented by types that write themselves as a JSON object.
+// WriteJSONObject writes the fields only; the object is opened and closed around it.
+type ObjectMarshaler interface {
+	WriteJSONObject(w *ObjectWriter)
+}
+
+// ValueMarshaler is implemented by types that write themselves as any JSON value.
+// WriteJSONValue must write exactly one value to w, which is positioned in place of the value.
+type ValueMarshaler interface {
+	WriteJSONValue(w *ArrayWriter)
+}
+
+// ObjectFunc adapts an ordinary function to the ObjectMarshaler interface.
+type ObjectFunc func(w *ObjectWriter)
+
+// WriteJSONObject calls f(w).
+func (f ObjectFunc) WriteJSONObject(w *ObjectWriter) {
+	f(w)
+}
+
+// ValueFunc adapts an ordinary function to the ValueMarshaler interface.
+type ValueFunc func(w *ArrayWriter)
+
+// WriteJSONValue calls f(w).
+func (f ValueFunc) WriteJSONValue(w *ArrayWriter) {
+	f(w)
+}
@@ -0,0 +1,158 @@
+package jsoni
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type point struct {
+	X, Y int64
+}
+
+func (p *point) WriteJSONObject(w *ObjectWriter) {
+	w.IntegerField("x", p.X)
+	w.IntegerField("y", p.Y)
+}
+
+type celsius float64
+
+func (c celsius) WriteJSONValue(w *ArrayWriter) {
+	w.FloatValue(float64(c))
+}
+
+type polyline []point
+
+func (l polyline) WriteJSONValue(w *ArrayWriter) {
+	arr := w.ArrayValue()
+	arr.Open()
+	for i := range l {
+		arr.AnyValue(&l[i])
+	}
+	arr.Close()
+}
+
+func TestMarshalers(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "object marshaler",
+			value:    &point{X: 1, Y: 2},
+			expected: `{"x":1,"y":2}`,
+		},
+		{
+			name:     "nil object marshaler",
+			value:    (*point)(nil),
+			expected: `null`,
+		},
+		{
+			name:     "value marshaler",
+			value:    celsius(21.5),
+			expected: `21.5`,
+		},
+		{
+			name:     "nested value marshaler",
+			value:    polyline{{1, 2}, {3, 4}},
+			expected: `[{"x":1,"y":2},{"x":3,"y":4}]`,
+		},
+		{
+			name: "object func",
+			value: ObjectFunc(func(w *ObjectWriter) {
+				w.StringField("name", "John")
+			}),
+			expected: `{"name":"John"}`,
+		},
+		{
+			name: "value func",
+			value: ValueFunc(func(w *ArrayWriter) {
+				w.BooleanValue(true)
+			}),
+			expected: `true`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.Open()
+			obj.AnyField("field", tt.value)
+			obj.Close()
+
+			result, err := obj.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+			if expected := `{"field":` + tt.expected + `}`; string(result) != expected {
+				t.Errorf("Expected %q, got %q", expected, string(result))
+			}
+
+			arr := NewArrayWriter(nil)
+			arr.Open()
+			arr.NullValue()
+			arr.AnyValue(tt.value)
+			arr.Close()
+
+			result, err = arr.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+			if expected := `[null,` + tt.expected + `]`; string(result) != expected {
+				t.Errorf("Expected %q, got %q", expected, string(result))
+			}
+		})
+	}
+}
+
+func TestMarshalers_Path(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		path  string
+	}{
+		{
+			name: "object marshaler",
+			value: ObjectFunc(func(w *ObjectWriter) {
+				w.FloatField("ratio", math.NaN())
+			}),
+			path: "/items/1/ratio",
+		},
+		{
+			name:  "value marshaler",
+			value: celsius(math.Inf(1)),
+			path:  "/items/1",
+		},
+		{
+			name: "value marshaler writing an object",
+			value: ValueFunc(func(w *ArrayWriter) {
+				w.Object(func(obj *ObjectWriter) {
+					obj.NumberField("n", "bad")
+				})
+			}),
+			path: "/items/1/n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.TrackPath()
+			obj.Open()
+			obj.Array("items", func(items *ArrayWriter) {
+				items.NullValue()
+				items.AnyValue(tt.value)
+			})
+			obj.Close()
+
+			var e *Error
+			if !errors.As(obj.Err(), &e) {
+				t.Fatalf("Expected *Error, got %v", obj.Err())
+			}
+			if e.Path != tt.path {
+				t.Errorf("Expected path %q, got %q", tt.path, e.Path)
+			}
+		})
+	}
+}
@@ -1,12 +1,22 @@
 package jsoni
 
-import "github.com/mailru/easyjson/jwriter"
+import (
+	"github.com/binadel/jsonw/internal/number"
+	"github.com/mailru/easyjson/jwriter"
+)
 
 // ObjectWriter builds a JSON object manually, supporting fields of various types,
 // including nested objects and arrays.
 type ObjectWriter struct {
-	writer     *jwriter.Writer
-	needsComma bool
+	writer      *jwriter.Writer
+	needsComma  bool
+	start       Mark          // position before the member, for omit-empty writers
+	parent      *bool         // parent's needsComma, set only for omit-empty writers
+	parentIndex *int          // parent's index, set only for omit-empty writers of an ArrayWriter
+	objects     *ObjectWriter // reused by Object
+	arrays      *ArrayWriter  // reused by Array
+	path        *path         // shared key and index chain, nil unless tracking
+	depth       int           // number of path segments leading to this object
 }
 
 // NewObjectWriter creates a new ObjectWriter given an optional writer from its parent node.
@@ -40,7 +50,9 @@ func (w *ObjectWriter) ObjectField(name string) ObjectWriter {
 
 	w.needsComma = true
 
-	return NewObjectWriter(w.writer)
+	obj := NewObjectWriter(w.writer)
+	obj.path, obj.depth = w.enter(name)
+	return obj
 }
 
 // ArrayField adds a nested array field and returns its writer for further modifications.
@@ -55,7 +67,75 @@ func (w *ObjectWriter) ArrayField(name string) ArrayWriter {
 
 	w.needsComma = true
 
-	return NewArrayWriter(w.writer)
+	arr := NewArrayWriter(w.writer)
+	arr.path, arr.depth = w.enter(name)
+	return arr
+}
+
+// Object adds a nested object field whose fields are written by fn.
+// The object is opened before fn is called and closed after it returns,
+// so the output stays balanced however fn returns.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ObjectWriter) Object(name string, fn func(obj *ObjectWriter)) {
+	if w.objects == nil {
+		w.objects = &ObjectWriter{}
+	}
+
+	obj := w.objects
+	obj.reuse(w.ObjectField(name))
+	obj.Open()
+	fn(obj)
+	obj.Close()
+}
+
+// Array adds a nested array field whose values are written by fn.
+// The array is opened before fn is called and closed after it returns,
+// so the output stays balanced however fn returns.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ObjectWriter) Array(name string, fn func(arr *ArrayWriter)) {
+	if w.arrays == nil {
+		w.arrays = &ArrayWriter{}
+	}
+
+	arr := w.arrays
+	arr.reuse(w.ArrayField(name))
+	arr.Open()
+	fn(arr)
+	arr.Close()
+}
+
+// Member adds a field whose name comes from data rather than code, such as a map key,
+// so it is escaped, and whose value is written by fn, which must write exactly one value.
+// The writer passed to fn is reused and must not be retained after fn returns.
+func (w *ObjectWriter) Member(key string, fn func(value *ArrayWriter)) {
+	if w.arrays == nil {
+		w.arrays = &ArrayWriter{}
+	}
+
+	p, depth := w.anyMember(key)
+	value := w.arrays
+	value.reuse(valueWriter(w.writer, p, depth))
+	fn(value)
+}
+
+// ObjectFieldOmitEmpty adds a nested object field like ObjectField, except that
+// the whole member, including its name, is removed on Close if no fields were written.
+func (w *ObjectWriter) ObjectFieldOmitEmpty(name string) ObjectWriter {
+	start := w.Mark()
+	obj := w.ObjectField(name)
+	obj.start = start
+	obj.parent = &w.needsComma
+	return obj
+}
+
+// ArrayFieldOmitEmpty adds a nested array field like ArrayField, except that
+// the whole member, including its name, is removed on Close if no values were written.
+func (w *ObjectWriter) ArrayFieldOmitEmpty(name string) ArrayWriter {
+	start := w.Mark()
+	arr := w.ArrayField(name)
+	arr.start = start
+	arr.parent = &w.needsComma
+	return arr
 }
 
 // StringField adds a string field to the object.
@@ -73,7 +153,13 @@ func (w *ObjectWriter) StringField(name, value string) {
 }
 
 // NumberField adds a number field to the object.
+// A value that is not a valid JSON number is reported as ErrInvalidNumber.
 func (w *ObjectWriter) NumberField(name, value string) {
+	if !number.Valid(value) {
+		w.fail(name, ErrInvalidNumber)
+		return
+	}
+
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
@@ -101,7 +187,13 @@ func (w *ObjectWriter) IntegerField(name string, value int64) {
 }
 
 // FloatField adds a float field to the object.
+// NaN and infinite values are reported as ErrUnsupportedFloat.
 func (w *ObjectWriter) FloatField(name string, value float64) {
+	if !validFloat(value) {
+		w.fail(name, ErrUnsupportedFloat)
+		return
+	}
+
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
@@ -142,7 +234,9 @@ func (w *ObjectWriter) NullField(name string) {
 }
 
 // AnyField adds a field of any type, automatically detecting its JSON representation.
+// Values implementing ObjectMarshaler or ValueMarshaler write themselves.
 func (w *ObjectWriter) AnyField(name string, value any) {
+	start := w.Mark()
 	if w.needsComma {
 		w.writer.RawByte(comma)
 	}
@@ -150,18 +244,175 @@ func (w *ObjectWriter) AnyField(name string, value any) {
 	w.writer.RawByte(quote)
 	w.writer.RawString(name)
 	w.writer.Raw(quoteColon, nil)
-	writeAny(w.writer, value)
+	p, depth := w.enter(name)
+	if err := writeAny(w.writer, p, depth, value); err != nil {
+		w.Rollback(start)
+		w.fail(name, err)
+		return
+	}
+
+	w.needsComma = true
+}
+
+// RawFields adds fields that are already encoded JSON, such as `"a":1,"b":2` cached from a previous write.
+// They are written as they are, without validation, and nothing is written for empty fields.
+func (w *ObjectWriter) RawFields(fields []byte) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	w.writer.Raw(fields, nil)
 
 	w.needsComma = true
 }
 
 // Close finishes the JSON object by writing '}'.
+// An empty object created by ObjectFieldOmitEmpty or ObjectValueOmitEmpty is removed instead.
 func (w *ObjectWriter) Close() {
+	if w.parent != nil && !w.needsComma {
+		truncate(w.writer, w.start.size)
+		*w.parent = w.start.needsComma
+		w.parent = nil
+		if w.parentIndex != nil {
+			*w.parentIndex = w.start.index
+			w.parentIndex = nil
+		}
+		return
+	}
+
 	w.writer.RawByte(closeBrace)
 
 	w.needsComma = false
 }
 
+// reuse replaces the writer state with next, keeping the writers cached for Object and Array.
+// Reused writers live on the heap, where storing a pointer costs a write barrier while the
+// garbage collector runs, so pointers are only stored when they change.
+func (w *ObjectWriter) reuse(next ObjectWriter) {
+	if w.writer != next.writer {
+		w.writer = next.writer
+	}
+	if w.parent != next.parent {
+		w.parent = next.parent
+	}
+	if w.parentIndex != next.parentIndex {
+		w.parentIndex = next.parentIndex
+	}
+	if w.path != next.path {
+		w.path = next.path
+	}
+	w.needsComma, w.start, w.depth = next.needsComma, next.start, next.depth
+}
+
+// Mark returns the current position, to be passed to Rollback.
+func (w *ObjectWriter) Mark() Mark {
+	return Mark{size: w.writer.Size(), needsComma: w.needsComma}
+}
+
+// Rollback discards everything written since the given mark was taken.
+// The mark must come from this writer and must not be older than its Open.
+func (w *ObjectWriter) Rollback(mark Mark) {
+	truncate(w.writer, mark.size)
+
+	w.needsComma = mark.needsComma
+}
+
+// TrackPath turns on path tracking for this writer and the writers nested in it,
+// so that Path and the errors they report carry the location in the document.
+// It must be called on the root writer, before anything is written.
+func (w *ObjectWriter) TrackPath() {
+	w.path = &path{}
+	w.depth = 0
+}
+
+// Path returns the JSON Pointer (RFC 6901) of this object within the document,
+// which is empty for the root and when path tracking is off.
+func (w *ObjectWriter) Path() string {
+	if w.path == nil {
+		return ""
+	}
+	return w.path.pointer(w.depth)
+}
+
+// rawMember starts a member whose name is already escaped, and returns the path state for its value.
+func (w *ObjectWriter) rawMember(raw, name string) (*path, int) {
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	w.writer.RawByte(quote)
+	w.writer.RawString(raw)
+	w.writer.Raw(quoteColon, nil)
+
+	w.needsComma = true
+
+	return w.enter(name)
+}
+
+// anyMember starts a member whose name comes from data rather than code, so it is escaped,
+// and returns the path state for its value.
+func (w *ObjectWriter) anyMember(name string) (*path, int) {
+	if w.needsComma {
+		w.writer.RawByte(comma)
+	}
+
+	w.writer.String(name)
+	w.writer.RawByte(colon)
+
+	w.needsComma = true
+
+	return w.enter(name)
+}
+
+// enter records that a member with the given name is being written,
+// and returns the path state for its nested writer.
+func (w *ObjectWriter) enter(name string) (*path, int) {
+	if w.path == nil {
+		return nil, 0
+	}
+	w.path.set(w.depth, keySegment(name))
+	return w.path, w.depth + 1
+}
+
+// fail records err for the member with the given name, unless an error was already recorded.
+func (w *ObjectWriter) fail(name string, err error) {
+	if w.writer.Error != nil {
+		return
+	}
+	e := &Error{Err: err}
+	if w.path != nil {
+		w.path.set(w.depth, keySegment(name))
+		e.Path = w.path.pointer(w.depth + 1)
+	}
+	w.writer.Error = e
+}
+
+// Err returns the first error recorded while writing, if any.
+func (w *ObjectWriter) Err() error {
+	return w.writer.Error
+}
+
+// Len returns the number of bytes written so far to the underlying writer.
+func (w *ObjectWriter) Len() int {
+	return w.writer.Size()
+}
+
+// Bytes returns the bytes written so far to the underlying writer, without consuming them.
+// The underlying writer is shared by the whole document, so this includes the output of
+// parent and sibling writers. The slice must not be modified and is only valid until the next write.
+func (w *ObjectWriter) Bytes() []byte {
+	return contents(w.writer)
+}
+
+// String returns the bytes written so far to the underlying writer as a string, without consuming them.
+func (w *ObjectWriter) String() string {
+	return string(contents(w.writer))
+}
+
 // BuildBytes returns the resulting JSON bytes.
 func (w *ObjectWriter) BuildBytes() ([]byte, error) {
 	return w.writer.BuildBytes()
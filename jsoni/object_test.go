@@ -2,6 +2,8 @@ package jsoni
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/mailru/easyjson/jwriter"
@@ -611,3 +613,290 @@ func TestObjectWriter_EmptyFields(t *testing.T) {
 		t.Error("Empty value field not handled correctly")
 	}
 }
+
+func TestObjectWriter_Rollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(obj *ObjectWriter)
+		expected string
+	}{
+		{
+			name: "first field",
+			write: func(obj *ObjectWriter) {
+				mark := obj.Mark()
+				obj.StringField("name", "John")
+				obj.Rollback(mark)
+				obj.IntegerField("age", 30)
+			},
+			expected: `{"age":30}`,
+		},
+		{
+			name: "later field",
+			write: func(obj *ObjectWriter) {
+				obj.StringField("name", "John")
+				mark := obj.Mark()
+				obj.IntegerField("age", 30)
+				obj.BooleanField("active", true)
+				obj.Rollback(mark)
+				obj.NullField("data")
+			},
+			expected: `{"name":"John","data":null}`,
+		},
+		{
+			name: "nested object",
+			write: func(obj *ObjectWriter) {
+				mark := obj.Mark()
+				nested := obj.ObjectField("nested")
+				nested.Open()
+				nested.StringField("secret", "value")
+				nested.Close()
+				obj.Rollback(mark)
+			},
+			expected: `{}`,
+		},
+		{
+			name: "large content spanning chunks",
+			write: func(obj *ObjectWriter) {
+				obj.StringField("keep", "me")
+				mark := obj.Mark()
+				for i := 0; i < 10000; i++ {
+					obj.IntegerField("i", int64(i))
+				}
+				obj.Rollback(mark)
+				obj.BooleanField("done", true)
+			},
+			expected: `{"keep":"me","done":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.Open()
+			tt.write(&obj)
+			obj.Close()
+
+			result, err := obj.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+
+			if string(result) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(result))
+			}
+		})
+	}
+}
+
+func TestObjectWriter_OmitEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(obj *ObjectWriter)
+		expected string
+	}{
+		{
+			name: "empty object as first field",
+			write: func(obj *ObjectWriter) {
+				nested := obj.ObjectFieldOmitEmpty("nested")
+				nested.Open()
+				nested.Close()
+				obj.StringField("name", "John")
+			},
+			expected: `{"name":"John"}`,
+		},
+		{
+			name: "empty array as later field",
+			write: func(obj *ObjectWriter) {
+				obj.StringField("name", "John")
+				tags := obj.ArrayFieldOmitEmpty("tags")
+				tags.Open()
+				tags.Close()
+				obj.IntegerField("age", 30)
+			},
+			expected: `{"name":"John","age":30}`,
+		},
+		{
+			name: "non-empty object is kept",
+			write: func(obj *ObjectWriter) {
+				nested := obj.ObjectFieldOmitEmpty("nested")
+				nested.Open()
+				nested.BooleanField("flag", true)
+				nested.Close()
+			},
+			expected: `{"nested":{"flag":true}}`,
+		},
+		{
+			name: "nested empty containers collapse",
+			write: func(obj *ObjectWriter) {
+				outer := obj.ObjectFieldOmitEmpty("outer")
+				outer.Open()
+				inner := outer.ArrayFieldOmitEmpty("inner")
+				inner.Open()
+				inner.Close()
+				outer.Close()
+			},
+			expected: `{}`,
+		},
+		{
+			name: "rolled back fields count as empty",
+			write: func(obj *ObjectWriter) {
+				obj.NullField("first")
+				nested := obj.ObjectFieldOmitEmpty("nested")
+				nested.Open()
+				mark := nested.Mark()
+				nested.StringField("secret", "value")
+				nested.Rollback(mark)
+				nested.Close()
+				obj.NullField("last")
+			},
+			expected: `{"first":null,"last":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			obj.Open()
+			tt.write(&obj)
+			obj.Close()
+
+			result, err := obj.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+
+			if string(result) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(result))
+			}
+		})
+	}
+}
+
+func TestObjectWriter_Closures(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.Open()
+	obj.StringField("name", "John")
+	obj.Object("profile", func(profile *ObjectWriter) {
+		profile.StringField("bio", "hello")
+		profile.Object("empty", func(*ObjectWriter) {})
+	})
+	obj.Array("tags", func(tags *ArrayWriter) {
+		for _, tag := range []string{"a", "", "b"} {
+			if tag == "" {
+				return
+			}
+			tags.StringValue(tag)
+		}
+	})
+	obj.Object("after", func(after *ObjectWriter) {
+		after.BooleanField("ok", true)
+	})
+	obj.Close()
+
+	result, err := obj.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `{"name":"John","profile":{"bio":"hello","empty":{}},"tags":["a"],"after":{"ok":true}}`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
+
+func TestObjectWriter_ClosuresAllocations(t *testing.T) {
+	var jw jwriter.Writer
+	obj := NewObjectWriter(&jw)
+	write := func() {
+		obj.Open()
+		obj.Object("profile", func(profile *ObjectWriter) {
+			profile.StringField("bio", "hello")
+		})
+		obj.Array("addresses", func(addresses *ArrayWriter) {
+			addresses.EachObject(10, func(i int, address *ObjectWriter) {
+				address.IntegerField("index", int64(i))
+				address.Array("lines", func(lines *ArrayWriter) {
+					lines.StringValue("line")
+				})
+			})
+		})
+		obj.Close()
+	}
+
+	buf := make([]byte, 0, 4096)
+	jw.Buffer.Buf = buf
+	write()
+	allocs := testing.AllocsPerRun(100, func() {
+		jw.Buffer.Buf = buf
+		write()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations once warmed up, got %v", allocs)
+	}
+}
+
+func TestObjectWriter_Member(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.TrackPath()
+	obj.Open()
+	obj.Member(`a"b`, func(value *ArrayWriter) {
+		value.IntegerValue(1)
+	})
+	obj.Member("<list>", func(value *ArrayWriter) {
+		value.Array(func(arr *ArrayWriter) {
+			arr.StringValue("x")
+		})
+	})
+	obj.Member("nested", func(value *ArrayWriter) {
+		value.Object(func(nested *ObjectWriter) {
+			nested.Member("inner", func(value *ArrayWriter) {
+				value.NullValue()
+			})
+		})
+	})
+	obj.Close()
+
+	result, err := obj.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `{"a\"b":1,"\u003clist\u003e":["x"],"nested":{"inner":null}}`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+
+	obj = NewObjectWriter(nil)
+	obj.TrackPath()
+	obj.Open()
+	obj.Member("a/b", func(value *ArrayWriter) {
+		value.FloatValue(math.NaN())
+	})
+	obj.Close()
+
+	var e *Error
+	if _, err := obj.BuildBytes(); !errors.As(err, &e) || e.Path != "/a~1b" {
+		t.Errorf("Expected an error at /a~1b, got %v", err)
+	}
+}
+
+func TestObjectWriter_RawFields(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.Open()
+	obj.RawFields(nil)
+	obj.RawFields([]byte(`"a":1,"b":{"c":[]}`))
+	obj.StringField("d", "x")
+	obj.RawFields([]byte(`"e":null`))
+	obj.RawFields(nil)
+	obj.Close()
+
+	result, err := obj.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes failed: %v", err)
+	}
+
+	expected := `{"a":1,"b":{"c":[]},"d":"x","e":null}`
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(result))
+	}
+}
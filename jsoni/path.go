@@ -0,0 +1,61 @@
+package jsoni
+
+import (
+	"strconv"
+	"strings"
+)
+
+// path is the chain of keys and indexes leading to the innermost open writer,
+// shared by all writers of a document once path tracking is turned on.
+type path struct {
+	segments []segment
+}
+
+// segment is an object key, or an array index when index is not negative.
+type segment struct {
+	key   string
+	index int
+}
+
+// set replaces the segment at the given depth, dropping any deeper ones.
+func (p *path) set(depth int, seg segment) {
+	p.segments = append(p.segments[:depth], seg)
+}
+
+// pointer returns the JSON Pointer (RFC 6901) of the first depth segments.
+func (p *path) pointer(depth int) string {
+	var b strings.Builder
+	for _, seg := range p.segments[:depth] {
+		seg.appendTo(&b)
+	}
+	return b.String()
+}
+
+// appendTo writes the segment to b as a JSON Pointer reference token, including the leading '/'.
+func (s segment) appendTo(b *strings.Builder) {
+	b.WriteByte('/')
+	if s.index >= 0 {
+		b.WriteString(strconv.Itoa(s.index))
+		return
+	}
+	for i := 0; i < len(s.key); i++ {
+		switch c := s.key[i]; c {
+		case '~':
+			b.WriteString("~0")
+		case '/':
+			b.WriteString("~1")
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// keySegment returns the segment of an object member.
+func keySegment(key string) segment {
+	return segment{key: key, index: -1}
+}
+
+// indexSegment returns the segment of an array value.
+func indexSegment(index int) segment {
+	return segment{index: index}
+}
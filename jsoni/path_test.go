@@ -0,0 +1,187 @@
+package jsoni
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	var paths []string
+
+	obj := NewObjectWriter(nil)
+	obj.TrackPath()
+	obj.Open()
+	paths = append(paths, obj.Path())
+	obj.Object("a/b", func(ab *ObjectWriter) {
+		paths = append(paths, ab.Path())
+		ab.Array("c~d", func(cd *ArrayWriter) {
+			paths = append(paths, cd.Path())
+			cd.IntegerValue(1)
+			cd.Object(func(item *ObjectWriter) {
+				paths = append(paths, item.Path())
+			})
+			nested := cd.ArrayValue()
+			nested.Open()
+			paths = append(paths, nested.Path())
+			nested.Close()
+		})
+	})
+	users := obj.ArrayField("users")
+	users.Open()
+	users.EachObject(2, func(i int, user *ObjectWriter) {
+		paths = append(paths, user.Path())
+	})
+	users.Close()
+	obj.Close()
+
+	expected := []string{"", "/a~1b", "/a~1b/c~0d", "/a~1b/c~0d/1", "/a~1b/c~0d/2", "/users/0", "/users/1"}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %d paths, got %d: %q", len(expected), len(paths), paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Path %d: expected %q, got %q", i, expected[i], paths[i])
+		}
+	}
+}
+
+func TestPath_Untracked(t *testing.T) {
+	obj := NewObjectWriter(nil)
+	obj.Open()
+	nested := obj.ObjectField("nested")
+	if path := nested.Path(); path != "" {
+		t.Errorf("Expected empty path without tracking, got %q", path)
+	}
+}
+
+func TestPath_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		track bool
+		write func(obj *ObjectWriter)
+		path  string
+		err   error
+	}{
+		{
+			name:  "NaN float field",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.Object("stats", func(stats *ObjectWriter) {
+					stats.FloatField("ratio", math.NaN())
+				})
+			},
+			path: "/stats/ratio",
+			err:  ErrUnsupportedFloat,
+		},
+		{
+			name:  "infinite float value",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.Array("values", func(values *ArrayWriter) {
+					values.FloatValue(1)
+					values.FloatValue(math.Inf(1))
+				})
+			},
+			path: "/values/1",
+			err:  ErrUnsupportedFloat,
+		},
+		{
+			name:  "bad number literal",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.Array("items", func(items *ArrayWriter) {
+					items.Object(func(item *ObjectWriter) {
+						item.NumberField("price", "12,5")
+					})
+				})
+			},
+			path: "/items/0/price",
+			err:  ErrInvalidNumber,
+		},
+		{
+			name:  "NaN in any value",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.Array("any", func(values *ArrayWriter) {
+					values.AnyValue(math.NaN())
+				})
+			},
+			path: "/any/0",
+			err:  ErrUnsupportedFloat,
+		},
+		{
+			name:  "after an omitted empty value",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.Array("values", func(values *ArrayWriter) {
+					empty := values.ObjectValueOmitEmpty()
+					empty.Open()
+					empty.Close()
+					nested := values.ArrayValueOmitEmpty()
+					nested.Open()
+					nested.Close()
+					values.FloatValue(math.NaN())
+				})
+			},
+			path: "/values/0",
+			err:  ErrUnsupportedFloat,
+		},
+		{
+			name:  "first error wins",
+			track: true,
+			write: func(obj *ObjectWriter) {
+				obj.NumberField("first", "x")
+				obj.NumberField("second", "y")
+			},
+			path: "/first",
+			err:  ErrInvalidNumber,
+		},
+		{
+			name: "untracked",
+			write: func(obj *ObjectWriter) {
+				obj.FloatField("ratio", math.Inf(-1))
+			},
+			path: "",
+			err:  ErrUnsupportedFloat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := NewObjectWriter(nil)
+			if tt.track {
+				obj.TrackPath()
+			}
+			obj.Open()
+			tt.write(&obj)
+			obj.Close()
+
+			_, err := obj.BuildBytes()
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Expected *Error, got %v", err)
+			}
+			if e.Path != tt.path {
+				t.Errorf("Expected path %q, got %q", tt.path, e.Path)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPath_Rollback(t *testing.T) {
+	arr := NewArrayWriter(nil)
+	arr.TrackPath()
+	arr.Open()
+	arr.IntegerValue(0)
+	mark := arr.Mark()
+	arr.IntegerValue(1)
+	arr.Rollback(mark)
+	obj := arr.ObjectValue()
+	if path := obj.Path(); path != "/1" {
+		t.Errorf("Expected path %q, got %q", "/1", path)
+	}
+}
@@ -7,6 +7,7 @@ var (
 	closeBracket   = byte(']')
 	quote          = byte('"')
 	comma          = byte(',')
+	colon          = byte(':')
 	quoteColon     = []byte(`":`)
 	nullValue      = []byte("null")
 	quoteColonNull = []byte(`":null`)
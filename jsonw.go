@@ -0,0 +1,51 @@
+// Package jsonw encodes arbitrary Go values to JSON through the jsoni writers.
+//
+// Values are written the way encoding/json writes them, honoring the same struct tags,
+// but the encoding plan of each type is built once and cached, and values implementing
+// jsoni.ObjectMarshaler, jsoni.ValueMarshaler or easyjson.Marshaler write themselves.
+package jsonw
+
+import (
+	"io"
+
+	"github.com/binadel/jsonw/internal/hooks"
+	_ "github.com/binadel/jsonw/jsoni" // sets the hooks
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Marshal returns the JSON encoding of v.
+func Marshal(v any) ([]byte, error) {
+	var writer jwriter.Writer
+	hooks.Encode(&writer, v)
+	return writer.BuildBytes()
+}
+
+// Encoder writes JSON values to an output stream.
+type Encoder struct {
+	out          io.Writer
+	noEscapeHTML bool
+}
+
+// NewEncoder returns a new encoder that writes to out.
+func NewEncoder(out io.Writer) *Encoder {
+	return &Encoder{out: out}
+}
+
+// SetEscapeHTML specifies whether the characters <, > and & are escaped inside JSON strings.
+// They are escaped by default, like encoding/json does.
+func (e *Encoder) SetEscapeHTML(on bool) {
+	e.noEscapeHTML = !on
+}
+
+// Encode writes the JSON encoding of v to the stream, followed by a newline.
+// Nothing is written if v cannot be encoded.
+func (e *Encoder) Encode(v any) error {
+	writer := &jwriter.Writer{NoEscapeHTML: e.noEscapeHTML}
+	hooks.Encode(writer, v)
+	if err := writer.Error; err != nil {
+		return err
+	}
+	writer.RawByte('\n')
+	_, err := writer.DumpTo(e.out)
+	return err
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"bytes"
+	js "encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+const anyDocument = `{"name":"John","age":30,"ratio":0.25,"ok":true,"none":null,` +
+	`"tags":["a",1,{"z":[],"<b>":"x"}],"profile":{"quote\"d":"<hi>","empty":{}}}`
+
+func TestJsondsFromAny(t *testing.T) {
+	for _, useNumber := range []bool{false, true} {
+		dec := js.NewDecoder(bytes.NewReader([]byte(anyDocument)))
+		if useNumber {
+			dec.UseNumber()
+		}
+		var decoded any
+		if err := dec.Decode(&decoded); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := js.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := jsonds.FromAny(decoded)
+		if err != nil {
+			t.Fatalf("FromAny failed: %v", err)
+		}
+		root, err := jsonds.New().Set("", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// encoding/json also sorts keys and escapes HTML, so both write the same bytes.
+		if got := mustBuild(t, root.Build); got != string(expected) {
+			t.Errorf("FromAny (UseNumber %v) wrote\n%s\nwant\n%s", useNumber, got, expected)
+		}
+	}
+
+	primitives := []any{int8(-1), uint16(2), uint64(math.MaxUint64), float32(0.1), js.Number("1e400"), "s", false, nil}
+	value, err := jsonds.FromAny(primitives)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, jsonds.NewArray(value).Build); got != `[[-1,2,18446744073709551615,0.1,1e400,"s",false,null]]` {
+		t.Errorf("FromAny wrote %s", got)
+	}
+
+	errorCases := []struct {
+		value any
+		err   error
+		msg   string
+	}{
+		{map[string]any{"a": []any{1, math.NaN()}}, jsoni.ErrUnsupportedFloat, "jsonds: /a/1: unsupported float value"},
+		{map[string]any{"a/b": js.Number("01")}, jsoni.ErrInvalidNumber, "jsonds: /a~1b: invalid number literal"},
+		{[]any{make(chan int)}, jsonds.ErrUnsupportedType, "jsonds: /0: unsupported type chan int"},
+		{struct{}{}, jsonds.ErrUnsupportedType, "jsonds: unsupported type struct {}"},
+	}
+	for _, tt := range errorCases {
+		_, err := jsonds.FromAny(tt.value)
+		if !errors.Is(err, tt.err) || err.Error() != tt.msg {
+			t.Errorf("FromAny(%v) returned %v, want %s", tt.value, err, tt.msg)
+		}
+	}
+}
+
+func TestJsondsToAny(t *testing.T) {
+	root := jsonds.New(
+		jsonds.String("name", "John"),
+		jsonds.Integer("age", 30),
+		jsonds.Float("ratio", 0.25),
+		jsonds.Number("big", "12345678901234567890"),
+		jsonds.Array("tags", jsonds.StringItem("a"), jsonds.NullItem(), jsonds.ObjectItem(jsonds.Boolean("ok", true))),
+		jsonds.String(`quote\"d`, "x"),
+		jsonds.ObjectOf("address", &addressObject{City: "Oxford"}),
+		jsonds.Custom("custom", func(w *jsoni.ObjectWriter) {
+			w.Array("custom", func(arr *jsoni.ArrayWriter) { arr.FloatValue(1.5) })
+		}),
+		jsonds.Any("failing", math.Inf(1)),
+	)
+
+	expected := map[string]any{
+		"name":    "John",
+		"age":     int64(30),
+		"ratio":   0.25,
+		"big":     js.Number("12345678901234567890"),
+		"tags":    []any{"a", nil, map[string]any{"ok": true}},
+		`quote"d`: "x",
+		"address": map[string]any{"street": "", "city": "Oxford", "zip": "", "country": ""},
+		"custom":  []any{js.Number("1.5")},
+	}
+	got := jsonds.ToAny(root)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ToAny returned\n%#v\nwant\n%#v", got, expected)
+	}
+
+	items := jsonds.ToAny(jsonds.New(jsonds.Array("items", jsonds.AnyItem(math.NaN()), jsonds.AnyItem(map[string]int{"n": 1}))))
+	if expected := map[string]any{"items": []any{nil, map[string]any{"n": js.Number("1")}}}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("ToAny returned %#v, want %#v", items, expected)
+	}
+
+	// FromAny turns the result back into the same nodes, in sorted order.
+	value, err := jsonds.FromAny(jsonds.ToAny(root[:6]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, jsonds.NewArray(value).Build); got != `[{"age":30,"big":12345678901234567890,"name":"John",`+
+		`"quote\"d":"x","ratio":0.25,"tags":["a",null,{"ok":true}]}]` {
+		t.Errorf("round trip wrote %s", got)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"testing"
+
+	json "github.com/binadel/jsonw/jsonds"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func buildUsersJsondsArena(a *json.Arena, users []User) json.RootArray {
+	items := a.Values(len(users))
+	for i, u := range users {
+		var tagsField json.Field
+		if u.Tags != nil {
+			tags := a.Values(len(u.Tags))
+			for j, tag := range u.Tags {
+				tags[j] = json.StringItem(tag)
+			}
+			tagsField = a.Array("tags", tags...)
+		} else {
+			tagsField = json.Null("tags")
+		}
+
+		var addressesField json.Field
+		if u.Addresses != nil {
+			addresses := a.Values(len(u.Addresses))
+			for j, addr := range u.Addresses {
+				addresses[j] = a.ObjectItem(
+					json.String("street", addr.Street),
+					json.String("city", addr.City),
+					json.String("zip", addr.Zip),
+					json.String("country", addr.Country),
+				)
+			}
+			addressesField = a.Array("addresses", addresses...)
+		} else {
+			addressesField = json.Null("addresses")
+		}
+
+		items[i] = a.ObjectItem(
+			json.Integer("id", u.ID),
+			json.String("name", u.Name),
+			json.String("email", u.Email),
+			json.Boolean("is_active", u.IsActive),
+			json.Integer("age", int64(u.Age)),
+			json.Float("balance", u.Balance),
+			tagsField,
+			a.Object("profile",
+				json.String("bio", u.Profile.Bio),
+				json.String("avatar_url", u.Profile.AvatarURL),
+			),
+			addressesField,
+		)
+	}
+	return a.NewArray(items...)
+}
+
+func writeUsersJsondsArena(a *json.Arena, users []User) []byte {
+	a.Reset()
+	b, _ := buildUsersJsondsArena(a, users).Build()
+	return b
+}
+
+func TestJsondsArena(t *testing.T) {
+	var a json.Arena
+	users := generateUsers(100)
+	expected := writeUsersJsonds(users)
+
+	for i := 0; i < 3; i++ {
+		if result := writeUsersJsondsArena(&a, users); string(result) != string(expected) {
+			t.Fatalf("Users json are different on build %d", i)
+		}
+	}
+}
+
+// TestJsondsArenaAllocations checks that building and writing users with a warmed up arena
+// allocates as much for one user as for many, the nested writers being cached per depth.
+func TestJsondsArenaAllocations(t *testing.T) {
+	var a json.Arena
+	jw := jwriter.Writer{}
+	buf := make([]byte, 0, 1<<16)
+
+	var counts []float64
+	for _, n := range []int{1, 10, 100} {
+		users := generateUsers(n)
+		write := func() {
+			a.Reset()
+			jw.Buffer.Buf = buf
+			if err := buildUsersJsondsArena(&a, users).BuildInto(&jw); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		write()
+		counts = append(counts, testing.AllocsPerRun(100, write))
+	}
+
+	if counts[0] != counts[1] || counts[0] != counts[2] {
+		t.Errorf("Expected a constant number of allocations, got %v for 1, 10 and 100 users", counts)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/binadel/jsonw"
+	"github.com/binadel/jsonw/jsonds"
 	"github.com/mailru/easyjson/jwriter"
 )
 
 var (
 	users = generateUsers(1000)
 	posts = generatePosts(users, 5)
+	dyn   = generateDynamic(users)
+
+	plainUsers = reflectUsers(users)
 )
 
 // ----------------- Benchmark: encoding/json -----------------
@@ -25,6 +30,12 @@ func BenchmarkEncodingJSON_Posts(b *testing.B) {
 	}
 }
 
+func BenchmarkEncodingJSON_Dynamic(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = json.Marshal(dyn)
+	}
+}
+
 // ----------------- Benchmark: easyjson ----------------------
 func BenchmarkEasyJSON_Users(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -59,6 +70,61 @@ func BenchmarkJsoniWriter_Posts(b *testing.B) {
 	}
 }
 
+func BenchmarkJsoniGenerated_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsoniGenerated(users)
+	}
+}
+
+func BenchmarkJsoniGenerated_Posts(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writePostsJsoniGenerated(posts)
+	}
+}
+
+func BenchmarkJsoniClosures_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsoniClosures(users)
+	}
+}
+
+func BenchmarkJsoniChain_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsoniChain(users)
+	}
+}
+
+func BenchmarkJsoniAnyValue_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsoniAny(users)
+	}
+}
+
+func BenchmarkJsoniAnyValueFallback_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsoniAnyFallback(users)
+	}
+}
+
+func BenchmarkJsoniAnyValue_Dynamic(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeAnyJsoni(dyn)
+	}
+}
+
+// ----------------- Benchmark: jsonw -------------------------
+func BenchmarkJsonwMarshal_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = jsonw.Marshal(plainUsers)
+	}
+}
+
+func BenchmarkEncodingJSONReflect_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = json.Marshal(plainUsers)
+	}
+}
+
 // ----------------- Benchmark: jsondi ------------------------
 func BenchmarkJsondiWriter_Users(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -79,6 +145,12 @@ func BenchmarkJsondfWriter_Users(b *testing.B) {
 	}
 }
 
+func BenchmarkJsondfTemplate_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsondfTemplate(users)
+	}
+}
+
 func BenchmarkJsondfWriter_Posts(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = writePostsJsondf(posts)
@@ -92,6 +164,26 @@ func BenchmarkJsondsWriter_Users(b *testing.B) {
 	}
 }
 
+// The tree benchmarks only build the nodes, measuring their size apart from the writing.
+func BenchmarkJsondsTree_Users(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = buildUsersJsonds(users)
+	}
+}
+
+func BenchmarkJsondsTree_Posts(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = buildPostsJsonds(posts)
+	}
+}
+
+func BenchmarkJsondsArena_Users(b *testing.B) {
+	var a jsonds.Arena
+	for i := 0; i < b.N; i++ {
+		_ = writeUsersJsondsArena(&a, users)
+	}
+}
+
 func BenchmarkJsondsWriter_Posts(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = writePostsJsonds(posts)
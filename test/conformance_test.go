@@ -0,0 +1,49 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsond/jsondtest"
+	_ "github.com/binadel/jsonw/jsondf"
+	_ "github.com/binadel/jsonw/jsondi"
+	_ "github.com/binadel/jsonw/jsonds"
+)
+
+func TestConformance(t *testing.T) {
+	var names []string
+	for _, impl := range jsond.Implementations() {
+		names = append(names, impl.Name)
+	}
+	if len(names) != 3 || names[0] != "jsondf" || names[1] != "jsondi" || names[2] != "jsonds" {
+		t.Fatalf("registered implementations are %v, want [jsondf jsondi jsonds]", names)
+	}
+
+	jsondtest.RunAll(t)
+}
+
+// TestConformanceReference checks the reference output on a few documents of the battery,
+// so that a regression in jsoni doesn't go unnoticed behind identical outputs.
+func TestConformanceReference(t *testing.T) {
+	expected := map[string]string{
+		"empty object":      `{}`,
+		"empty array":       `[]`,
+		"literals":          `[true,false,null,[null],{"true":true,"null":null}]`,
+		"empty containers":  `{"object":{},"array":[],"nested":[[],{},[[]]]}`,
+		"marshalers":        `[{"x":1,"y":2},[3,4],{"object":{"x":5,"y":6},"value":[7,8]},{"x":9,"y":10},[11,12]]`,
+		"unsupported float": ``,
+	}
+	for _, doc := range jsondtest.Documents() {
+		want, ok := expected[doc.Name]
+		if !ok {
+			continue
+		}
+		got, err := jsondtest.Reference(doc.Root)
+		if string(got) != want {
+			t.Errorf("%s: got %s, want %s", doc.Name, got, want)
+		}
+		if (err != nil) != (want == "") {
+			t.Errorf("%s: unexpected error %v", doc.Name, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// writeLocation writes a member computed at write time, as a user-defined node would.
+func writeLocation(w *jsoni.ObjectWriter) {
+	w.Object("location", func(obj *jsoni.ObjectWriter) {
+		obj.FloatField("lat", 52.52)
+		obj.FloatField("lng", 13.405)
+	})
+}
+
+// writeCoordinates writes a value computed at write time, as a user-defined node would.
+func writeCoordinates(w *jsoni.ArrayWriter) {
+	w.Array(func(arr *jsoni.ArrayWriter) {
+		arr.FloatValue(52.52)
+		arr.FloatValue(13.405)
+	})
+}
+
+func TestCustomNodes(t *testing.T) {
+	expected := `{"name":"Berlin","location":{"lat":52.52,"lng":13.405},` +
+		`"points":[["x"],[52.52,13.405]],"nested":{"location":{"lat":52.52,"lng":13.405}}}`
+
+	builds := map[string]func() ([]byte, error){
+		"jsondf": jsondf.New(
+			jsondf.String("name", "Berlin"),
+			jsondf.Custom("location", writeLocation),
+			jsondf.Array("points",
+				jsondf.ArrayItem(jsondf.StringItem("x")),
+				jsondf.CustomItem(writeCoordinates),
+			),
+			jsondf.Object("nested", jsondf.Custom("location", writeLocation)),
+		).Build,
+		"jsondi": jsondi.New(
+			jsondi.String("name", "Berlin"),
+			jsondi.Custom("location", writeLocation),
+			jsondi.Array("points",
+				jsondi.ArrayItem(jsondi.StringItem("x")),
+				jsondi.CustomItem(writeCoordinates),
+			),
+			jsondi.Object("nested", jsondi.Custom("location", writeLocation)),
+		).Build,
+		"jsonds": jsonds.New(
+			jsonds.String("name", "Berlin"),
+			jsonds.Custom("location", writeLocation),
+			jsonds.Array("points",
+				jsonds.ArrayItem(jsonds.StringItem("x")),
+				jsonds.CustomItem(writeCoordinates),
+			),
+			jsonds.Object("nested", jsonds.Custom("location", writeLocation)),
+		).Build,
+	}
+
+	for name, build := range builds {
+		t.Run(name, func(t *testing.T) {
+			result, err := build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if string(result) != expected {
+				t.Errorf("Expected %s, got %s", expected, result)
+			}
+		})
+	}
+}
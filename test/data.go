@@ -65,6 +65,37 @@ func generatePosts(users []User, postsPerUser int) []Post {
 	return posts
 }
 
+// generateDynamic converts users into the map[string]any form of dynamically typed data.
+func generateDynamic(users []User) []any {
+	items := make([]any, len(users))
+	for i, u := range users {
+		addresses := make([]any, len(u.Addresses))
+		for j, a := range u.Addresses {
+			addresses[j] = map[string]string{
+				"street":  a.Street,
+				"city":    a.City,
+				"zip":     a.Zip,
+				"country": a.Country,
+			}
+		}
+		items[i] = map[string]any{
+			"id":        u.ID,
+			"name":      u.Name,
+			"email":     u.Email,
+			"is_active": u.IsActive,
+			"age":       u.Age,
+			"balance":   u.Balance,
+			"tags":      u.Tags,
+			"profile": map[string]any{
+				"bio":        u.Profile.Bio,
+				"avatar_url": u.Profile.AvatarURL,
+			},
+			"addresses": addresses,
+		}
+	}
+	return items
+}
+
 func randomString(n int) string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	b := make([]byte, n)
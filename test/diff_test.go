@@ -0,0 +1,117 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+func TestDiff(t *testing.T) {
+	expected := `[{"op":"replace","path":"/name","value":"Jane"},` +
+		`{"op":"remove","path":"/tags/1"},` +
+		`{"op":"add","path":"/profile/a~1b","value":{"x":[1]}}]`
+
+	diffs := map[string]func() ([]byte, error){
+		"jsondf": func() ([]byte, error) {
+			return jsond.Diff(
+				jsondf.New(jsondf.String("name", "John"), jsondf.Array("tags", jsondf.StringItem("a"), jsondf.StringItem("b")), jsondf.Object("profile")),
+				jsondf.New(jsondf.String("name", "Jane"), jsondf.Array("tags", jsondf.StringItem("a")), jsondf.Object("profile",
+					jsondf.Object("a/b", jsondf.Array("x", jsondf.IntegerItem(1))))),
+				jsond.DiffOptions{})
+		},
+		"jsondi": func() ([]byte, error) {
+			return jsond.Diff(
+				jsondi.New(jsondi.String("name", "John"), jsondi.Array("tags", jsondi.StringItem("a"), jsondi.StringItem("b")), jsondi.Object("profile")),
+				jsondi.New(jsondi.String("name", "Jane"), jsondi.Array("tags", jsondi.StringItem("a")), jsondi.Object("profile",
+					jsondi.Object("a/b", jsondi.Array("x", jsondi.IntegerItem(1))))),
+				jsond.DiffOptions{})
+		},
+		"jsonds": func() ([]byte, error) {
+			return jsond.Diff(
+				jsonds.New(jsonds.String("name", "John"), jsonds.Array("tags", jsonds.StringItem("a"), jsonds.StringItem("b")), jsonds.Object("profile")),
+				jsonds.New(jsonds.String("name", "Jane"), jsonds.Array("tags", jsonds.StringItem("a")), jsonds.Object("profile",
+					jsonds.Object("a/b", jsonds.Array("x", jsonds.IntegerItem(1))))),
+				jsond.DiffOptions{})
+		},
+		"mixed": func() ([]byte, error) {
+			return jsond.Diff(
+				jsondf.New(jsondf.String("name", "John"), jsondf.Array("tags", jsondf.StringItem("a"), jsondf.StringItem("b")), jsondf.Object("profile")),
+				jsonds.New(jsonds.String("name", "Jane"), jsonds.Array("tags", jsonds.StringItem("a")), jsonds.Custom("profile", func(w *jsoni.ObjectWriter) {
+					w.Object("profile", func(p *jsoni.ObjectWriter) {
+						p.Object("a/b", func(o *jsoni.ObjectWriter) { o.Array("x", func(a *jsoni.ArrayWriter) { a.IntegerValue(1) }) })
+					})
+				})),
+				jsond.DiffOptions{})
+		},
+	}
+	for name, diff := range diffs {
+		patch, err := diff()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(patch) != expected {
+			t.Errorf("%s diff is\n%s\nwant\n%s", name, patch, expected)
+		}
+	}
+}
+
+func TestDiffBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		opts     jsond.DiffOptions
+		expected string
+	}{
+		{"equal", `{"a":[1,{"b":null}]}`, "{ \"a\" : [ 1, {\"b\": null} ] }\n", jsond.DiffOptions{}, `[]`},
+		{"root", `{"a":1}`, `[1]`, jsond.DiffOptions{}, `[{"op":"replace","path":"","value":[1]}]`},
+		{"kind", `{"a":1}`, `{"a":"1"}`, jsond.DiffOptions{}, `[{"op":"replace","path":"/a","value":"1"}]`},
+		{"literals", `[true,null,false]`, `[false,null,true]`, jsond.DiffOptions{},
+			`[{"op":"replace","path":"/0","value":false},{"op":"replace","path":"/2","value":true}]`},
+		{"strings", `["é","a"]`, `["é","b"]`, jsond.DiffOptions{}, `[{"op":"replace","path":"/1","value":"b"}]`},
+		{"grow", `[1]`, `[1,2,[3]]`, jsond.DiffOptions{},
+			`[{"op":"add","path":"/-","value":2},{"op":"add","path":"/-","value":[3]}]`},
+		{"shrink", `[1,2,3]`, `[1]`, jsond.DiffOptions{},
+			`[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`},
+		{"compact values", `{}`, "{\"a\": {\n  \"b\": [1, 2]\n}}", jsond.DiffOptions{},
+			`[{"op":"add","path":"/a","value":{"b":[1,2]}}]`},
+		{"escaped names", `{"~":1,"/":2}`, `{"~":2,"/":2}`, jsond.DiffOptions{}, `[{"op":"replace","path":"/~0","value":2}]`},
+		{"numbers", `[1,1.0,1e2,0.10]`, `[1.0,1,100,0.1]`, jsond.DiffOptions{},
+			`[{"op":"replace","path":"/0","value":1.0},{"op":"replace","path":"/1","value":1},` +
+				`{"op":"replace","path":"/2","value":100},{"op":"replace","path":"/3","value":0.1}]`},
+		{"numeric numbers", `[1,1.0,1e2,0.10,2]`, `[1.0,1,100,0.1,3]`, jsond.DiffOptions{NumericNumbers: true},
+			`[{"op":"replace","path":"/4","value":3}]`},
+		{"removed member", `{"a":1,"b":2,"c":3}`, `{"a":1,"c":4}`, jsond.DiffOptions{},
+			`[{"op":"remove","path":"/b"},{"op":"replace","path":"/c","value":4}]`},
+		{"added member", `{"a":1,"c":3}`, `{"a":1,"c":3,"d":4}`, jsond.DiffOptions{}, `[{"op":"add","path":"/d","value":4}]`},
+		{"inserted member", `{"a":1,"c":3}`, `{"a":1,"b":2,"c":3}`, jsond.DiffOptions{},
+			`[{"op":"remove","path":"/c"},{"op":"add","path":"/b","value":2},{"op":"add","path":"/c","value":3}]`},
+		{"inserted member unordered", `{"a":1,"c":3}`, `{"a":1,"b":2,"c":3}`, jsond.DiffOptions{UnorderedKeys: true},
+			`[{"op":"add","path":"/b","value":2}]`},
+		{"moved member", `{"a":1,"b":2,"c":3}`, `{"b":2,"c":3,"a":1}`, jsond.DiffOptions{},
+			`[{"op":"remove","path":"/a"},{"op":"add","path":"/a","value":1}]`},
+		{"moved member unordered", `{"a":1,"b":{"x":1,"y":2}}`, `{"b":{"y":2,"x":1},"a":1}`, jsond.DiffOptions{UnorderedKeys: true}, `[]`},
+		{"unordered changes", `{"a":1,"b":2}`, `{"c":3,"b":1}`, jsond.DiffOptions{UnorderedKeys: true},
+			`[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":1},{"op":"add","path":"/c","value":3}]`},
+	}
+	for _, tt := range tests {
+		patch, err := jsond.DiffBytes([]byte(tt.a), []byte(tt.b), tt.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(patch) != tt.expected {
+			t.Errorf("%s diff is\n%s\nwant\n%s", tt.name, patch, tt.expected)
+		}
+	}
+
+	if _, err := jsond.DiffBytes([]byte(`{"a":`), []byte(`{}`), jsond.DiffOptions{}); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if _, err := jsond.Diff(jsonds.New(jsonds.Any("f", func() {})), jsonds.New(), jsond.DiffOptions{}); err == nil {
+		t.Error("expected the build error of the first document")
+	}
+}
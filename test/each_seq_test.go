@@ -0,0 +1,109 @@
+//go:build go1.23
+
+package test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+)
+
+func TestEachSeqConstructors(t *testing.T) {
+	expected := eachExpected(t)
+	matrix := [][]int{{1}, {2, 3}}
+	// Sorted keys make the sequence order match encoding/json.
+	scores := func(yield func(string, []int) bool) {
+		for _, key := range slices.Sorted(maps.Keys(eachScores)) {
+			if !yield(key, eachScores[key]) {
+				return
+			}
+		}
+	}
+
+	builds := map[string]func() ([]byte, error){
+		"jsondf": jsondf.New(
+			jsondf.EachSeq("addresses", slices.Values(eachAddresses), func(a Address) jsondf.Value {
+				return jsondf.ObjectItem(
+					jsondf.String("street", a.Street),
+					jsondf.String("city", a.City),
+					jsondf.String("zip", a.Zip),
+					jsondf.String("country", a.Country),
+				)
+			}),
+			jsondf.EachSeq("matrix", slices.Values(matrix), func(row []int) jsondf.Value {
+				return jsondf.ArrayItem(jsondf.EachSeqItem(slices.Values(row), func(i int) jsondf.Value {
+					return jsondf.IntegerItem(int64(i))
+				}))
+			}),
+			jsondf.MapOfSeq("scores", scores, func(scores []int) jsondf.Value {
+				if scores == nil {
+					return jsondf.NullItem()
+				}
+				return jsondf.EachSeqItem(slices.Values(scores), func(i int) jsondf.Value {
+					return jsondf.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+		"jsondi": jsondi.New(
+			jsondi.EachSeq("addresses", slices.Values(eachAddresses), func(a Address) jsondi.Value {
+				return jsondi.ObjectItem(
+					jsondi.String("street", a.Street),
+					jsondi.String("city", a.City),
+					jsondi.String("zip", a.Zip),
+					jsondi.String("country", a.Country),
+				)
+			}),
+			jsondi.EachSeq("matrix", slices.Values(matrix), func(row []int) jsondi.Value {
+				return jsondi.ArrayItem(jsondi.EachSeqItem(slices.Values(row), func(i int) jsondi.Value {
+					return jsondi.IntegerItem(int64(i))
+				}))
+			}),
+			jsondi.MapOfSeq("scores", scores, func(scores []int) jsondi.Value {
+				if scores == nil {
+					return jsondi.NullItem()
+				}
+				return jsondi.EachSeqItem(slices.Values(scores), func(i int) jsondi.Value {
+					return jsondi.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+		"jsonds": jsonds.New(
+			jsonds.EachSeq("addresses", slices.Values(eachAddresses), func(a Address) jsonds.Value {
+				return jsonds.ObjectItem(
+					jsonds.String("street", a.Street),
+					jsonds.String("city", a.City),
+					jsonds.String("zip", a.Zip),
+					jsonds.String("country", a.Country),
+				)
+			}),
+			jsonds.EachSeq("matrix", slices.Values(matrix), func(row []int) jsonds.Value {
+				return jsonds.ArrayItem(jsonds.EachSeqItem(slices.Values(row), func(i int) jsonds.Value {
+					return jsonds.IntegerItem(int64(i))
+				}))
+			}),
+			jsonds.MapOfSeq("scores", scores, func(scores []int) jsonds.Value {
+				if scores == nil {
+					return jsonds.NullItem()
+				}
+				return jsonds.EachSeqItem(slices.Values(scores), func(i int) jsonds.Value {
+					return jsonds.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+	}
+
+	for name, build := range builds {
+		result, err := build()
+		if err != nil {
+			t.Errorf("%s: Build failed: %v", name, err)
+			continue
+		}
+		if string(result) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", name, expected, result)
+		}
+	}
+}
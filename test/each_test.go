@@ -0,0 +1,148 @@
+package test
+
+import (
+	js "encoding/json"
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+)
+
+// eachAddresses and eachScores are the collections written by the iteration constructors.
+var (
+	eachAddresses = []Address{
+		{Street: "1 Main St", City: "Springfield", Zip: "12345", Country: "US"},
+		{Street: "2 High St", City: "Oxford", Zip: "OX1", Country: "UK"},
+	}
+	eachScores = map[string][]int{"b": {2, 3}, "a": {1}, `<"q">`: nil, "empty": {}}
+)
+
+func eachExpected(t *testing.T) string {
+	t.Helper()
+	expected, err := js.Marshal(map[string]any{
+		"addresses": eachAddresses,
+		"scores":    eachScores,
+		"matrix":    [][][]int{{{1}}, {{2, 3}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encoding/json sorts the top-level keys, so the documents below are written in that order.
+	return string(expected)
+}
+
+func TestEachConstructors(t *testing.T) {
+	expected := eachExpected(t)
+	matrix := [][]int{{1}, {2, 3}}
+
+	builds := map[string]func() ([]byte, error){
+		"jsondf": jsondf.New(
+			jsondf.Each("addresses", eachAddresses, func(a Address) jsondf.Value {
+				return jsondf.ObjectItem(
+					jsondf.String("street", a.Street),
+					jsondf.String("city", a.City),
+					jsondf.String("zip", a.Zip),
+					jsondf.String("country", a.Country),
+				)
+			}),
+			jsondf.Each("matrix", matrix, func(row []int) jsondf.Value {
+				return jsondf.ArrayItem(jsondf.EachItem(row, func(i int) jsondf.Value {
+					return jsondf.IntegerItem(int64(i))
+				}))
+			}),
+			jsondf.MapOf("scores", eachScores, func(scores []int) jsondf.Value {
+				if scores == nil {
+					return jsondf.NullItem()
+				}
+				return jsondf.EachItem(scores, func(i int) jsondf.Value {
+					return jsondf.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+		"jsondi": jsondi.New(
+			jsondi.Each("addresses", eachAddresses, func(a Address) jsondi.Value {
+				return jsondi.ObjectItem(
+					jsondi.String("street", a.Street),
+					jsondi.String("city", a.City),
+					jsondi.String("zip", a.Zip),
+					jsondi.String("country", a.Country),
+				)
+			}),
+			jsondi.Each("matrix", matrix, func(row []int) jsondi.Value {
+				return jsondi.ArrayItem(jsondi.EachItem(row, func(i int) jsondi.Value {
+					return jsondi.IntegerItem(int64(i))
+				}))
+			}),
+			jsondi.MapOf("scores", eachScores, func(scores []int) jsondi.Value {
+				if scores == nil {
+					return jsondi.NullItem()
+				}
+				return jsondi.EachItem(scores, func(i int) jsondi.Value {
+					return jsondi.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+		"jsonds": jsonds.New(
+			jsonds.Each("addresses", eachAddresses, func(a Address) jsonds.Value {
+				return jsonds.ObjectItem(
+					jsonds.String("street", a.Street),
+					jsonds.String("city", a.City),
+					jsonds.String("zip", a.Zip),
+					jsonds.String("country", a.Country),
+				)
+			}),
+			jsonds.Each("matrix", matrix, func(row []int) jsonds.Value {
+				return jsonds.ArrayItem(jsonds.EachItem(row, func(i int) jsonds.Value {
+					return jsonds.IntegerItem(int64(i))
+				}))
+			}),
+			jsonds.MapOf("scores", eachScores, func(scores []int) jsonds.Value {
+				if scores == nil {
+					return jsonds.NullItem()
+				}
+				return jsonds.EachItem(scores, func(i int) jsonds.Value {
+					return jsonds.IntegerItem(int64(i))
+				})
+			}),
+		).Build,
+	}
+
+	for name, build := range builds {
+		result, err := build()
+		if err != nil {
+			t.Errorf("%s: Build failed: %v", name, err)
+			continue
+		}
+		if string(result) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", name, expected, result)
+		}
+	}
+}
+
+func TestEachConstructorsLazy(t *testing.T) {
+	calls := 0
+	items := []int{1, 2, 3}
+	root := jsondf.NewArray(jsondf.EachItem(items, func(i int) jsondf.Value {
+		calls++
+		return jsondf.IntegerItem(int64(i))
+	}), jsondf.MapOfItem(map[string]int{"x": 1}, func(i int) jsondf.Value {
+		calls++
+		return jsondf.IntegerItem(int64(i))
+	}))
+	if calls != 0 {
+		t.Fatalf("expected no calls before Build, got %d", calls)
+	}
+
+	items[0] = 10
+	result, err := root.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if expected := `[[10,2,3],{"x":1}]`; string(result) != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+}
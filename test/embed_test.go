@@ -0,0 +1,120 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// declarativeTree holds the same subtrees built with one of the declarative packages.
+type declarativeTree struct {
+	profile interface {
+		WriteFields(*jsoni.ObjectWriter)
+		BuildInto(*jwriter.Writer) error
+	}
+	tags interface {
+		WriteValues(*jsoni.ArrayWriter)
+		BuildInto(*jwriter.Writer) error
+	}
+	field func(*jsoni.ObjectWriter)
+	value func(*jsoni.ArrayWriter)
+}
+
+func declarativeTrees() map[string]declarativeTree {
+	return map[string]declarativeTree{
+		"jsondf": {
+			profile: jsondf.New(jsondf.String("bio", "Gopher"), jsondf.Integer("age", 30)),
+			tags:    jsondf.NewArray(jsondf.StringItem("go"), jsondf.StringItem("json")),
+			field:   jsondf.Boolean("active", true).WriteField,
+			value:   jsondf.ObjectItem(jsondf.Float("score", 9.5)).WriteValue,
+		},
+		"jsondi": {
+			profile: jsondi.New(jsondi.String("bio", "Gopher"), jsondi.Integer("age", 30)),
+			tags:    jsondi.NewArray(jsondi.StringItem("go"), jsondi.StringItem("json")),
+			field:   jsondi.Boolean("active", true).WriteField,
+			value:   jsondi.ObjectItem(jsondi.Float("score", 9.5)).WriteValue,
+		},
+		"jsonds": {
+			profile: jsonds.New(jsonds.String("bio", "Gopher"), jsonds.Integer("age", 30)),
+			tags:    jsonds.NewArray(jsonds.StringItem("go"), jsonds.StringItem("json")),
+			field:   jsonds.Boolean("active", true).WriteField,
+			value:   jsonds.ObjectItem(jsonds.Float("score", 9.5)).WriteValue,
+		},
+	}
+}
+
+func TestDeclarativeInImperative(t *testing.T) {
+	expected := `{"id":1,"profile":{"bio":"Gopher","age":30},"tags":["go","json"],"active":true,"items":[{"score":9.5},null]}`
+
+	for name, tree := range declarativeTrees() {
+		t.Run(name, func(t *testing.T) {
+			w := jsoni.NewObjectWriter(nil)
+			w.Open()
+			w.IntegerField("id", 1)
+			w.Object("profile", tree.profile.WriteFields)
+			w.Array("tags", tree.tags.WriteValues)
+			tree.field(&w)
+			w.Array("items", func(arr *jsoni.ArrayWriter) {
+				tree.value(arr)
+				arr.NullValue()
+			})
+			w.Close()
+
+			result, err := w.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+			if string(result) != expected {
+				t.Errorf("Expected %s, got %s", expected, result)
+			}
+		})
+	}
+}
+
+func TestDeclarativeBuildInto(t *testing.T) {
+	expected := `{"bio":"Gopher","age":30}` + "\n" + `["go","json"]`
+
+	for name, tree := range declarativeTrees() {
+		t.Run(name, func(t *testing.T) {
+			var w jwriter.Writer
+			if err := tree.profile.BuildInto(&w); err != nil {
+				t.Fatalf("BuildInto failed: %v", err)
+			}
+			w.RawByte('\n')
+			if err := tree.tags.BuildInto(&w); err != nil {
+				t.Fatalf("BuildInto failed: %v", err)
+			}
+
+			result, err := w.BuildBytes()
+			if err != nil {
+				t.Fatalf("BuildBytes failed: %v", err)
+			}
+			if string(result) != expected {
+				t.Errorf("Expected %s, got %s", expected, result)
+			}
+		})
+	}
+}
+
+func TestDeclarativeBuildIntoError(t *testing.T) {
+	roots := map[string]interface{ BuildInto(*jwriter.Writer) error }{
+		"jsondf": jsondf.New(jsondf.Float("nan", math.NaN())),
+		"jsondi": jsondi.New(jsondi.Float("nan", math.NaN())),
+		"jsonds": jsonds.New(jsonds.Float("nan", math.NaN())),
+	}
+
+	for name, root := range roots {
+		t.Run(name, func(t *testing.T) {
+			var w jwriter.Writer
+			if err := root.BuildInto(&w); !errors.Is(err, jsoni.ErrUnsupportedFloat) {
+				t.Errorf("Expected ErrUnsupportedFloat, got %v", err)
+			}
+		})
+	}
+}
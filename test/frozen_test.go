@@ -0,0 +1,158 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// frozenCounter returns a custom field and value writing static data, and counts their writes.
+func frozenCounter() (*atomic.Int32, func(*jsoni.ObjectWriter), func(*jsoni.ArrayWriter)) {
+	var writes atomic.Int32
+	field := func(w *jsoni.ObjectWriter) {
+		writes.Add(1)
+		w.Array("countries", func(arr *jsoni.ArrayWriter) {
+			arr.StringValue("DE")
+			arr.StringValue("FR")
+		})
+	}
+	value := func(w *jsoni.ArrayWriter) {
+		writes.Add(1)
+		w.Object(func(obj *jsoni.ObjectWriter) {
+			obj.BooleanField("beta", true)
+		})
+	}
+	return &writes, field, value
+}
+
+func TestFrozen(t *testing.T) {
+	expected := `{"id":1,"flags":{"dark_mode":true,"limit":10},"countries":["DE","FR"],` +
+		`"items":[{"beta":true},"x",{"beta":true}]}`
+
+	type frozenBuild struct {
+		writes *atomic.Int32
+		build  func() ([]byte, error)
+	}
+	builds := map[string]frozenBuild{}
+
+	writes, field, value := frozenCounter()
+	item := jsondf.FrozenItem(jsondf.CustomItem(value))
+	builds["jsondf"] = frozenBuild{writes, jsondf.New(
+		jsondf.Integer("id", 1),
+		jsondf.Frozen(jsondf.Object("flags",
+			jsondf.Boolean("dark_mode", true),
+			jsondf.Integer("limit", 10),
+		)),
+		jsondf.Frozen(jsondf.Custom("countries", field)),
+		jsondf.Array("items", item, jsondf.StringItem("x"), item),
+	).Build}
+
+	writes, field, value = frozenCounter()
+	itemi := jsondi.FrozenItem(jsondi.CustomItem(value))
+	builds["jsondi"] = frozenBuild{writes, jsondi.New(
+		jsondi.Integer("id", 1),
+		jsondi.Frozen(jsondi.Object("flags",
+			jsondi.Boolean("dark_mode", true),
+			jsondi.Integer("limit", 10),
+		)),
+		jsondi.Frozen(jsondi.Custom("countries", field)),
+		jsondi.Array("items", itemi, jsondi.StringItem("x"), itemi),
+	).Build}
+
+	writes, field, value = frozenCounter()
+	items := jsonds.FrozenItem(jsonds.CustomItem(value))
+	builds["jsonds"] = frozenBuild{writes, jsonds.New(
+		jsonds.Integer("id", 1),
+		jsonds.Frozen(jsonds.Object("flags",
+			jsonds.Boolean("dark_mode", true),
+			jsonds.Integer("limit", 10),
+		)),
+		jsonds.Frozen(jsonds.Custom("countries", field)),
+		jsonds.Array("items", items, jsonds.StringItem("x"), items),
+	).Build}
+
+	for name, b := range builds {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					result, err := b.build()
+					if err != nil {
+						t.Errorf("%s: Build failed: %v", name, err)
+						return
+					}
+					if string(result) != expected {
+						t.Errorf("%s: expected\n%s\ngot\n%s", name, expected, result)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := b.writes.Load(); n != 2 {
+			t.Errorf("%s: expected the frozen subtrees to be written twice, once each, got %d", name, n)
+		}
+	}
+}
+
+func TestFrozenError(t *testing.T) {
+	builds := map[string]func() ([]byte, error){
+		"jsondf": jsondf.New(jsondf.Frozen(jsondf.Object("o", jsondf.Float("f", math.NaN())))).Build,
+		"jsondi": jsondi.New(jsondi.Frozen(jsondi.Object("o", jsondi.Float("f", math.NaN())))).Build,
+		"jsonds": jsonds.New(jsonds.Frozen(jsonds.Object("o", jsonds.Float("f", math.NaN())))).Build,
+	}
+
+	for name, build := range builds {
+		for i := 0; i < 2; i++ {
+			if _, err := build(); !errors.Is(err, jsoni.ErrUnsupportedFloat) {
+				t.Errorf("%s: expected %v on build %d, got %v", name, jsoni.ErrUnsupportedFloat, i, err)
+			}
+		}
+	}
+}
+
+func TestFrozenEscapeHTML(t *testing.T) {
+	builds := map[string]func(w *jwriter.Writer) error{
+		"jsondf": jsondf.New(
+			jsondf.Frozen(jsondf.String("html", "<b>")),
+			jsondf.Array("items", jsondf.FrozenItem(jsondf.StringItem("a&b"))),
+		).BuildInto,
+		"jsondi": jsondi.New(
+			jsondi.Frozen(jsondi.String("html", "<b>")),
+			jsondi.Array("items", jsondi.FrozenItem(jsondi.StringItem("a&b"))),
+		).BuildInto,
+		"jsonds": jsonds.New(
+			jsonds.Frozen(jsonds.String("html", "<b>")),
+			jsonds.Array("items", jsonds.FrozenItem(jsonds.StringItem("a&b"))),
+		).BuildInto,
+	}
+
+	for name, build := range builds {
+		// Every setting gets its own encoding, whichever is written first.
+		for _, noEscapeHTML := range []bool{false, true, false, true} {
+			expected := `{"html":"\u003cb\u003e","items":["a\u0026b"]}`
+			if noEscapeHTML {
+				expected = `{"html":"<b>","items":["a&b"]}`
+			}
+
+			w := jwriter.Writer{NoEscapeHTML: noEscapeHTML}
+			if err := build(&w); err != nil {
+				t.Fatalf("%s: BuildInto failed: %v", name, err)
+			}
+			if result := string(w.Buffer.BuildBytes()); result != expected {
+				t.Errorf("%s: expected %s, got %s", name, expected, result)
+			}
+		}
+	}
+}
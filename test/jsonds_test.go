@@ -3,6 +3,7 @@ package test
 import (
 	js "encoding/json"
 	"testing"
+	"unsafe"
 
 	json "github.com/binadel/jsonw/jsonds"
 )
@@ -25,7 +26,22 @@ func TestJsonds(t *testing.T) {
 	}
 }
 
+// TestJsondsNodeSize guards the compact layout of the nodes, which share their payload slots.
+func TestJsondsNodeSize(t *testing.T) {
+	if size := unsafe.Sizeof(json.Value{}); size > 72 {
+		t.Errorf("Value is %d bytes, expected at most 72", size)
+	}
+	if size := unsafe.Sizeof(json.Field{}); size > 88 {
+		t.Errorf("Field is %d bytes, expected at most 88", size)
+	}
+}
+
 func writeUsersJsonds(users []User) []byte {
+	b, _ := buildUsersJsonds(users).Build()
+	return b
+}
+
+func buildUsersJsonds(users []User) json.RootArray {
 	items := make([]json.Value, len(users))
 	for i, u := range users {
 		var tagsField json.Field
@@ -70,12 +86,15 @@ func writeUsersJsonds(users []User) []byte {
 			addressesField,
 		)
 	}
-	r := json.NewArray(items...)
-	b, _ := r.Build()
-	return b
+	return json.NewArray(items...)
 }
 
 func writePostsJsonds(posts []Post) []byte {
+	b, _ := buildPostsJsonds(posts).Build()
+	return b
+}
+
+func buildPostsJsonds(posts []Post) json.RootArray {
 	items := make([]json.Value, len(posts))
 	for i, p := range posts {
 		var tagsField json.Field
@@ -114,7 +133,5 @@ func writePostsJsonds(posts []Post) []byte {
 			commentsField,
 		)
 	}
-	r := json.NewArray(items...)
-	b, _ := r.Build()
-	return b
+	return json.NewArray(items...)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
 )
 
 func TestJsoni(t *testing.T) {
@@ -25,6 +26,16 @@ func TestJsoni(t *testing.T) {
 	}
 }
 
+func TestJsoniClosures(t *testing.T) {
+	users := generateUsers(100)
+	usersJson, _ := json.Marshal(users)
+	usersJsoni := writeUsersJsoniClosures(users)
+
+	if string(usersJsoni) != string(usersJson) {
+		t.Error("Users json are different")
+	}
+}
+
 func TestComplexJSONStructure(t *testing.T) {
 	// Create a complex JSON structure with nested objects and arrays
 	obj := jsoni.NewObjectWriter(nil)
@@ -619,6 +630,41 @@ func TestAnyFieldIntegration(t *testing.T) {
 	}
 }
 
+func TestJsoniGenerated(t *testing.T) {
+	users := generateUsers(100)
+	posts := generatePosts(users, 5)
+	usersJson, _ := json.Marshal(users)
+	postsJson, _ := json.Marshal(posts)
+
+	if usersJsoni := writeUsersJsoniGenerated(users); string(usersJsoni) != string(usersJson) {
+		t.Error("Users json are different")
+	}
+	if postsJsoni := writePostsJsoniGenerated(posts); string(postsJsoni) != string(postsJson) {
+		t.Error("Posts json are different")
+	}
+}
+
+func TestJsoniAnyValue(t *testing.T) {
+	users := generateUsers(100)
+	usersJson, _ := json.Marshal(users)
+
+	if usersJsoni := writeUsersJsoniAny(users); string(usersJsoni) != string(usersJson) {
+		t.Error("Users json are different")
+	}
+	if usersJsoni := writeUsersJsoniAnyFallback(users); string(usersJsoni) != string(usersJson) {
+		t.Error("Users json are different with the reflection fallback")
+	}
+}
+
+func TestJsoniAnyValueDynamic(t *testing.T) {
+	dyn := generateDynamic(generateUsers(100))
+	dynJson, _ := json.Marshal(dyn)
+
+	if dynJsoni := writeAnyJsoni(dyn); string(dynJsoni) != string(dynJson) {
+		t.Error("Dynamic json are different")
+	}
+}
+
 func writeUsersJsoni(users []User) []byte {
 	writer := jsoni.NewArrayWriter(nil)
 	writer.Open()
@@ -719,3 +765,165 @@ func writePostsJsoni(posts []Post) []byte {
 	bytes, _ := writer.BuildBytes()
 	return bytes
 }
+
+func writeUsersJsoniClosures(users []User) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	writer.EachObject(len(users), func(i int, obj *jsoni.ObjectWriter) {
+		u := &users[i]
+		obj.IntegerField("id", u.ID)
+		obj.StringField("name", u.Name)
+		obj.StringField("email", u.Email)
+		obj.BooleanField("is_active", u.IsActive)
+		obj.IntegerField("age", int64(u.Age))
+		obj.FloatField("balance", u.Balance)
+
+		if u.Tags != nil {
+			obj.Array("tags", func(tags *jsoni.ArrayWriter) {
+				for _, t := range u.Tags {
+					tags.StringValue(t)
+				}
+			})
+		} else {
+			obj.NullField("tags")
+		}
+
+		obj.Object("profile", func(profile *jsoni.ObjectWriter) {
+			profile.StringField("bio", u.Profile.Bio)
+			profile.StringField("avatar_url", u.Profile.AvatarURL)
+		})
+
+		if u.Addresses != nil {
+			obj.Array("addresses", func(addrArr *jsoni.ArrayWriter) {
+				addrArr.EachObject(len(u.Addresses), func(j int, addrObj *jsoni.ObjectWriter) {
+					a := &u.Addresses[j]
+					addrObj.StringField("street", a.Street)
+					addrObj.StringField("city", a.City)
+					addrObj.StringField("zip", a.Zip)
+					addrObj.StringField("country", a.Country)
+				})
+			})
+		} else {
+			obj.NullField("addresses")
+		}
+	})
+	writer.Close()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+func writeUsersJsoniChain(users []User) []byte {
+	writer := jsoni.NewArrayChain(nil)
+	writer.Open()
+	for _, u := range users {
+		obj := writer.Obj().
+			Int("id", u.ID).
+			Str("name", u.Name).
+			Str("email", u.Email).
+			Bool("is_active", u.IsActive).
+			Int("age", int64(u.Age)).
+			Float("balance", u.Balance)
+
+		if u.Tags != nil {
+			tags := obj.Arr("tags")
+			for _, t := range u.Tags {
+				tags.Str(t)
+			}
+			tags.End()
+		} else {
+			obj.Null("tags")
+		}
+
+		obj.Obj("profile").
+			Str("bio", u.Profile.Bio).
+			Str("avatar_url", u.Profile.AvatarURL).
+			End()
+
+		if u.Addresses != nil {
+			addrArr := obj.Arr("addresses")
+			for _, a := range u.Addresses {
+				addrArr.Obj().
+					Str("street", a.Street).
+					Str("city", a.City).
+					Str("zip", a.Zip).
+					Str("country", a.Country).
+					EndItem()
+			}
+			addrArr.End()
+		} else {
+			obj.Null("addresses")
+		}
+
+		obj.EndItem()
+	}
+	writer.EndItem()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+func writeUsersJsoniGenerated(users []User) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	for _, u := range users {
+		obj := writer.ObjectValue()
+		obj.Open()
+		u.WriteJSON(&obj)
+		obj.Close()
+	}
+	writer.Close()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+func writePostsJsoniGenerated(posts []Post) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	for _, p := range posts {
+		obj := writer.ObjectValue()
+		obj.Open()
+		p.WriteJSON(&obj)
+		obj.Close()
+	}
+	writer.Close()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+// plainUser has the fields of User but none of its generated marshalers.
+type plainUser User
+
+// easyjsonUser only has the easyjson marshaler of User, which AnyValue checks after the jsoni ones.
+type easyjsonUser User
+
+func (v easyjsonUser) MarshalEasyJSON(w *jwriter.Writer) {
+	User(v).MarshalEasyJSON(w)
+}
+
+func writeUsersJsoniAny(users []User) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	for _, u := range users {
+		writer.AnyValue(easyjsonUser(u))
+	}
+	writer.Close()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+func writeUsersJsoniAnyFallback(users []User) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.Open()
+	for _, u := range users {
+		writer.AnyValue(plainUser(u))
+	}
+	writer.Close()
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
+
+func writeAnyJsoni(value any) []byte {
+	writer := jsoni.NewArrayWriter(nil)
+	writer.AnyValue(value)
+	bytes, _ := writer.BuildBytes()
+	return bytes
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/binadel/jsonw"
+)
+
+// reflectUser mirrors User with types that have no generated marshalers,
+// so that every level of it is written by reflection.
+type reflectUser struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Email     string           `json:"email"`
+	IsActive  bool             `json:"is_active"`
+	Age       int              `json:"age"`
+	Balance   float64          `json:"balance"`
+	Tags      []string         `json:"tags"`
+	Profile   reflectProfile   `json:"profile"`
+	Addresses []reflectAddress `json:"addresses"`
+}
+
+type reflectProfile struct {
+	Bio       string `json:"bio"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type reflectAddress struct {
+	Street  string `json:"street"`
+	City    string `json:"city"`
+	Zip     string `json:"zip"`
+	Country string `json:"country"`
+}
+
+func reflectUsers(users []User) []reflectUser {
+	result := make([]reflectUser, len(users))
+	for i, u := range users {
+		result[i] = reflectUser{
+			ID:       u.ID,
+			Name:     u.Name,
+			Email:    u.Email,
+			IsActive: u.IsActive,
+			Age:      u.Age,
+			Balance:  u.Balance,
+			Tags:     u.Tags,
+			Profile:  reflectProfile(u.Profile),
+		}
+		if u.Addresses != nil {
+			result[i].Addresses = make([]reflectAddress, len(u.Addresses))
+			for j, a := range u.Addresses {
+				result[i].Addresses[j] = reflectAddress(a)
+			}
+		}
+	}
+	return result
+}
+
+func TestJsonwMarshal(t *testing.T) {
+	users := generateUsers(100)
+	posts := generatePosts(users, 5)
+
+	for name, value := range map[string]any{
+		"users":         users,
+		"posts":         posts,
+		"reflect users": reflectUsers(users),
+		"dynamic":       generateDynamic(users),
+		"duration":      time.Second,
+		"error":         errors.New("failed"),
+		"nested":        map[string]any{"duration": time.Second, "errors": []any{errors.New("failed")}},
+	} {
+		expected, _ := json.Marshal(value)
+		result, err := jsonw.Marshal(value)
+		if err != nil {
+			t.Fatalf("Marshal %s failed: %v", name, err)
+		}
+		if string(result) != string(expected) {
+			t.Errorf("Marshal %s json are different", name)
+		}
+	}
+}
+
+func TestJsonwEncoder(t *testing.T) {
+	users := reflectUsers(generateUsers(10))
+
+	var expected, result bytes.Buffer
+	jsonEncoder := json.NewEncoder(&expected)
+	jsonEncoder.SetEscapeHTML(false)
+	encoder := jsonw.NewEncoder(&result)
+	encoder.SetEscapeHTML(false)
+	for _, u := range users {
+		_ = jsonEncoder.Encode(u)
+		if err := encoder.Encode(u); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	if result.String() != expected.String() {
+		t.Error("Encoded streams are different")
+	}
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+type addressObject Address
+
+func (a *addressObject) WriteJSONObject(w *jsoni.ObjectWriter) {
+	w.StringField("street", a.Street)
+	w.StringField("city", a.City)
+	w.StringField("zip", a.Zip)
+	w.StringField("country", a.Country)
+}
+
+type tagList []string
+
+func (l tagList) WriteJSONValue(w *jsoni.ArrayWriter) {
+	w.Array(func(arr *jsoni.ArrayWriter) {
+		for _, tag := range l {
+			arr.StringValue(tag)
+		}
+	})
+}
+
+func TestMarshalersInDeclarativeTrees(t *testing.T) {
+	addr := &addressObject{Street: "Main", City: "Springfield", Zip: "12345", Country: "US"}
+	tags := tagList{"go", "json"}
+
+	expected := `{"address":{"street":"Main","city":"Springfield","zip":"12345","country":"US"},"tags":["go","json"],` +
+		`"items":[{"street":"Main","city":"Springfield","zip":"12345","country":"US"},["go","json"]]}`
+
+	builds := map[string]func() ([]byte, error){
+		"jsondf": jsondf.New(
+			jsondf.ObjectOf("address", addr),
+			jsondf.ValueOf("tags", tags),
+			jsondf.Array("items", jsondf.ObjectOfItem(addr), jsondf.ValueOfItem(tags)),
+		).Build,
+		"jsondi": jsondi.New(
+			jsondi.ObjectOf("address", addr),
+			jsondi.ValueOf("tags", tags),
+			jsondi.Array("items", jsondi.ObjectOfItem(addr), jsondi.ValueOfItem(tags)),
+		).Build,
+		"jsonds": jsonds.New(
+			jsonds.ObjectOf("address", addr),
+			jsonds.ValueOf("tags", tags),
+			jsonds.Array("items", jsonds.ObjectOfItem(addr), jsonds.ValueOfItem(tags)),
+		).Build,
+	}
+
+	for name, build := range builds {
+		t.Run(name, func(t *testing.T) {
+			result, err := build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if string(result) != expected {
+				t.Errorf("Expected %s, got %s", expected, result)
+			}
+		})
+	}
+}
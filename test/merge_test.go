@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// mergeLayers are a configuration with its defaults, an environment layer and a tenant layer,
+// described once and built with each package.
+var mergeLayers = []jsond.Node{
+	{Kind: jsond.Object, Children: []jsond.Node{
+		{Kind: jsond.String, Name: "name", Value: "app"},
+		{Kind: jsond.Object, Name: "db", Children: []jsond.Node{
+			{Kind: jsond.String, Name: "host", Value: "localhost"},
+			{Kind: jsond.Integer, Name: "port", Value: int64(5432)},
+			{Kind: jsond.Object, Name: "pool", Children: []jsond.Node{{Kind: jsond.Integer, Name: "size", Value: int64(4)}}},
+		}},
+		{Kind: jsond.Array, Name: "features", Children: []jsond.Node{{Kind: jsond.String, Value: "a"}}},
+		{Kind: jsond.Boolean, Name: "debug", Value: true},
+		{Kind: jsond.Integer, Name: "limit", Value: int64(10)},
+	}},
+	{Kind: jsond.Object, Children: []jsond.Node{
+		{Kind: jsond.Null, Name: "debug"},
+		{Kind: jsond.Object, Name: "db", Children: []jsond.Node{
+			{Kind: jsond.String, Name: "host", Value: "db.internal"},
+			{Kind: jsond.Null, Name: "pool"},
+			{Kind: jsond.String, Name: "user", Value: "svc"},
+		}},
+		{Kind: jsond.Null, Name: "missing"},
+	}},
+	{Kind: jsond.Object, Children: []jsond.Node{
+		{Kind: jsond.Array, Name: "features", Children: []jsond.Node{{Kind: jsond.String, Value: "b"}, {Kind: jsond.Null}}},
+		{Kind: jsond.Object, Name: "limit", Children: []jsond.Node{
+			{Kind: jsond.Integer, Name: "rps", Value: int64(100)},
+			{Kind: jsond.Null, Name: "burst"},
+		}},
+		{Kind: jsond.Object, Name: "tenant", Children: []jsond.Node{
+			{Kind: jsond.String, Name: "id", Value: "t1"},
+			{Kind: jsond.Object, Name: "empty", Children: []jsond.Node{{Kind: jsond.Null, Name: "x"}}},
+		}},
+		{Kind: jsond.String, Name: "name", Value: "tenant-app"},
+	}},
+}
+
+func TestMerge(t *testing.T) {
+	expected := `{"name":"tenant-app","db":{"host":"db.internal","port":5432,"user":"svc"},` +
+		`"features":["b",null],"limit":{"rps":100},"tenant":{"id":"t1","empty":{}}}`
+
+	merges := map[string]func() (string, string){
+		"jsondf": func() (string, string) {
+			base := jsondf.API.RootObject(mergeLayers[0])
+			merged := jsondf.Merge(jsondf.Merge(base, jsondf.API.RootObject(mergeLayers[1])), jsondf.API.RootObject(mergeLayers[2]))
+			return mustBuild(t, merged.Build), mustBuild(t, base.Build)
+		},
+		"jsondi": func() (string, string) {
+			base := jsondi.API.RootObject(mergeLayers[0])
+			merged := jsondi.Merge(jsondi.Merge(base, jsondi.API.RootObject(mergeLayers[1])), jsondi.API.RootObject(mergeLayers[2]))
+			return mustBuild(t, merged.Build), mustBuild(t, base.Build)
+		},
+		"jsonds": func() (string, string) {
+			base := jsonds.API.RootObject(mergeLayers[0])
+			merged := jsonds.Merge(jsonds.Merge(base, jsonds.API.RootObject(mergeLayers[1])), jsonds.API.RootObject(mergeLayers[2]))
+			return mustBuild(t, merged.Build), mustBuild(t, base.Build)
+		},
+	}
+	baseJSON, _ := jsonds.API.Build(mergeLayers[0])
+	for name, merge := range merges {
+		merged, base := merge()
+		if merged != expected {
+			t.Errorf("%s merged\n%s\nwant\n%s", name, merged, expected)
+		}
+		if base != string(baseJSON) {
+			t.Errorf("%s modified the base: %s", name, base)
+		}
+	}
+}
+
+func TestMergeOpaqueNodes(t *testing.T) {
+	// Nodes only known once written are replaced as a whole, and keep writing like they did.
+	base := jsondf.New(
+		jsondf.Each("ids", []int64{1, 2}, func(id int64) jsondf.Value { return jsondf.IntegerItem(id) }),
+		jsondf.Frozen(jsondf.Object("static", jsondf.Boolean("on", true))),
+		jsondf.Object("kept", jsondf.Integer("x", 1)),
+	)
+	patch := jsondf.New(
+		jsondf.Object("ids", jsondf.Integer("count", 2)),
+		jsondf.Object("kept", jsondf.Slot[int64]("y")),
+	)
+	merged := jsondf.Merge(base, patch)
+
+	if got := mustBuild(t, jsondf.Merge(base, jsondf.New()).Build); got != `{"ids":[1,2],"static":{"on":true},"kept":{"x":1}}` {
+		t.Errorf("merged an empty patch into %s", got)
+	}
+	tmpl, err := jsondf.Compile(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := tmpl.Bind()
+	jsondf.Bind(b, "y", int64(3))
+	if got := mustBuild(t, b.Execute); got != `{"ids":{"count":2},"static":{"on":true},"kept":{"x":1,"y":3}}` {
+		t.Errorf("merged %s", got)
+	}
+}
+
+func TestMergeUnnamedCustom(t *testing.T) {
+	// Functions used as fields write members whose names are unknown, so they are never merged.
+	member := func(name, value string) jsondf.FieldFunc {
+		return func(w *jsoni.ObjectWriter) { w.StringField(name, value) }
+	}
+	base := jsondf.New(jsondf.String("a", "x"), member("keep", "1"))
+	patch := jsondf.New(member("added", "2"), jsondf.String("a", "y"))
+	if got := mustBuild(t, jsondf.Merge(base, patch).Build); got != `{"a":"y","keep":"1","added":"2"}` {
+		t.Errorf("merged %s", got)
+	}
+}
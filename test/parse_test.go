@@ -0,0 +1,80 @@
+package test
+
+import (
+	js "encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/binadel/jsonw/jsonds"
+)
+
+func TestJsondsParse(t *testing.T) {
+	documents := []string{
+		pointerBaseJSON,
+		`{}`,
+		`{"a":{},"b":[],"c":[[],{}],"d":null,"e":false}`,
+		`{"numbers":[0,-0,1.50,1e400,-2.5E-3,12345678901234567890]}`,
+		`{"<b>":"\u003chi\u003e \"x\" \\ \n é \u2028","dup":1,"dup":2,"a\/b":"/"}`,
+		`{"z":1,"a":{"y":2,"b":3}}`,
+	}
+	for _, doc := range documents {
+		data := []byte(doc)
+		root, err := jsonds.Parse(data)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %v", doc, err)
+			continue
+		}
+		// The tree doesn't point into the input, which can be reused.
+		for i := range data {
+			data[i] = ' '
+		}
+		if got := mustBuild(t, root.Build); got != doc {
+			t.Errorf("Parse(%s) wrote %s", doc, got)
+		}
+	}
+
+	users := generateUsers(3)
+	posts, err := js.Marshal(generatePosts(users, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	array, err := jsonds.ParseArray(posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, array.Build); got != string(posts) {
+		t.Errorf("ParseArray wrote\n%s\nwant\n%s", got, posts)
+	}
+
+	// Whitespace is dropped, and the tree can be edited like a built one.
+	root, err := jsonds.Parse([]byte("{\n  \"id\": 1,\n  \"tags\": [ \"a\" ]\n}\n"))
+	if err == nil {
+		root, err = root.Insert("/tags/-", jsonds.StringItem("b"))
+	}
+	if err == nil {
+		var value jsonds.Value
+		value, err = jsonds.ParseValue([]byte(`{"x":1.0}`))
+		root, _ = root.Set("/id", value)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, root.Build); got != `{"id":{"x":1.0},"tags":["a","b"]}` {
+		t.Errorf("edited %s", got)
+	}
+	if v, err := root.Get("/tags/1"); err != nil || jsonds.DescribeItem(v).Value != "b" {
+		t.Errorf("Get returned %v, %v", jsonds.DescribeItem(v), err)
+	}
+
+	for _, doc := range []string{``, `{"a":}`, `[1,]`, `{"a":1} x`, `01`, `{"a" 1}`, `["\x"]`, `{"a":1`} {
+		if _, err := jsonds.ParseValue([]byte(doc)); !errors.Is(err, jsonds.ErrInvalidJSON) {
+			t.Errorf("ParseValue(%q) returned %v, want ErrInvalidJSON", doc, err)
+		}
+	}
+	if _, err := jsonds.Parse([]byte(`[1]`)); err == nil {
+		t.Error("Parse accepted an array")
+	}
+	if _, err := jsonds.ParseArray([]byte(`{}`)); err == nil {
+		t.Error("ParseArray accepted an object")
+	}
+}
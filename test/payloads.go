@@ -1,5 +1,7 @@
 package test
 
+//go:generate go run ../cmd/jsonwgen -type User,Profile,Address,Post,Comment
+
 type User struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
@@ -0,0 +1,122 @@
+// Code generated by jsonwgen. DO NOT EDIT.
+
+package test
+
+import (
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// WriteJSON writes the fields of User to w.
+func (v User) WriteJSON(w *jsoni.ObjectWriter) {
+	w.IntegerField("id", v.ID)
+	w.StringField("name", v.Name)
+	w.StringField("email", v.Email)
+	w.BooleanField("is_active", v.IsActive)
+	w.IntegerField("age", int64(v.Age))
+	w.AnyField("balance", v.Balance)
+	if v.Tags == nil {
+		w.NullField("tags")
+	} else {
+		arr1 := w.ArrayField("tags")
+		arr1.Open()
+		for _, item2 := range v.Tags {
+			arr1.StringValue(item2)
+		}
+		arr1.Close()
+	}
+	obj3 := w.ObjectField("profile")
+	obj3.Open()
+	v.Profile.WriteJSON(&obj3)
+	obj3.Close()
+	if v.Addresses == nil {
+		w.NullField("addresses")
+	} else {
+		arr4 := w.ArrayField("addresses")
+		arr4.Open()
+		for _, item5 := range v.Addresses {
+			obj6 := arr4.ObjectValue()
+			obj6.Open()
+			item5.WriteJSON(&obj6)
+			obj6.Close()
+		}
+		arr4.Close()
+	}
+}
+
+// WriteJSONObject implements jsoni.ObjectMarshaler.
+func (v User) WriteJSONObject(w *jsoni.ObjectWriter) {
+	v.WriteJSON(w)
+}
+
+// WriteJSON writes the fields of Profile to w.
+func (v Profile) WriteJSON(w *jsoni.ObjectWriter) {
+	w.StringField("bio", v.Bio)
+	w.StringField("avatar_url", v.AvatarURL)
+}
+
+// WriteJSONObject implements jsoni.ObjectMarshaler.
+func (v Profile) WriteJSONObject(w *jsoni.ObjectWriter) {
+	v.WriteJSON(w)
+}
+
+// WriteJSON writes the fields of Address to w.
+func (v Address) WriteJSON(w *jsoni.ObjectWriter) {
+	w.StringField("street", v.Street)
+	w.StringField("city", v.City)
+	w.StringField("zip", v.Zip)
+	w.StringField("country", v.Country)
+}
+
+// WriteJSONObject implements jsoni.ObjectMarshaler.
+func (v Address) WriteJSONObject(w *jsoni.ObjectWriter) {
+	v.WriteJSON(w)
+}
+
+// WriteJSON writes the fields of Post to w.
+func (v Post) WriteJSON(w *jsoni.ObjectWriter) {
+	w.IntegerField("id", v.ID)
+	w.IntegerField("user_id", v.UserID)
+	w.StringField("title", v.Title)
+	w.StringField("content", v.Content)
+	if v.Tags == nil {
+		w.NullField("tags")
+	} else {
+		arr1 := w.ArrayField("tags")
+		arr1.Open()
+		for _, item2 := range v.Tags {
+			arr1.StringValue(item2)
+		}
+		arr1.Close()
+	}
+	w.IntegerField("likes", int64(v.Likes))
+	if v.Comments == nil {
+		w.NullField("comments")
+	} else {
+		arr3 := w.ArrayField("comments")
+		arr3.Open()
+		for _, item4 := range v.Comments {
+			obj5 := arr3.ObjectValue()
+			obj5.Open()
+			item4.WriteJSON(&obj5)
+			obj5.Close()
+		}
+		arr3.Close()
+	}
+}
+
+// WriteJSONObject implements jsoni.ObjectMarshaler.
+func (v Post) WriteJSONObject(w *jsoni.ObjectWriter) {
+	v.WriteJSON(w)
+}
+
+// WriteJSON writes the fields of Comment to w.
+func (v Comment) WriteJSON(w *jsoni.ObjectWriter) {
+	w.IntegerField("id", v.ID)
+	w.IntegerField("user_id", v.UserID)
+	w.StringField("message", v.Message)
+}
+
+// WriteJSONObject implements jsoni.ObjectMarshaler.
+func (v Comment) WriteJSONObject(w *jsoni.ObjectWriter) {
+	v.WriteJSON(w)
+}
@@ -0,0 +1,264 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+func pointerBase() jsonds.RootObject {
+	return jsonds.New(
+		jsonds.Integer("id", 1),
+		jsonds.Object("profile",
+			jsonds.String("bio", "hello"),
+			jsonds.Array("tags", jsonds.StringItem("a"), jsonds.StringItem("b")),
+		),
+		jsonds.String("a/b~c", "escaped"),
+		jsonds.Boolean("deprecated", true),
+	)
+}
+
+const pointerBaseJSON = `{"id":1,"profile":{"bio":"hello","tags":["a","b"]},"a/b~c":"escaped","deprecated":true}`
+
+func mustBuild(t *testing.T, build func() ([]byte, error)) string {
+	t.Helper()
+	out, err := build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestJsondsPointerGet(t *testing.T) {
+	root := pointerBase()
+	tests := map[string]string{
+		"":                `{"id":1,"profile":{"bio":"hello","tags":["a","b"]},"a/b~c":"escaped","deprecated":true}`,
+		"/profile":        `{"bio":"hello","tags":["a","b"]}`,
+		"/profile/tags":   `["a","b"]`,
+		"/profile/tags/1": `"b"`,
+		"/a~1b~0c":        `"escaped"`,
+	}
+	for pointer, expected := range tests {
+		v, err := root.Get(pointer)
+		if err != nil {
+			t.Errorf("Get(%q) failed: %v", pointer, err)
+			continue
+		}
+		if got := mustBuild(t, jsonds.NewArray(v).Build); got != "["+expected+"]" {
+			t.Errorf("Get(%q) = %s, want %s", pointer, got, expected)
+		}
+	}
+
+	for _, pointer := range []string{"/missing", "/profile/tags/2", "/profile/tags/01", "/profile/tags/-", "/id/x", "/profile/tags/+1"} {
+		if _, err := root.Get(pointer); !errors.Is(err, jsonds.ErrNotFound) {
+			t.Errorf("Get(%q) returned %v, want ErrNotFound", pointer, err)
+		}
+	}
+	for _, pointer := range []string{"id", "/a~2", "/a~"} {
+		if _, err := root.Get(pointer); !errors.Is(err, jsonds.ErrInvalidPointer) {
+			t.Errorf("Get(%q) returned %v, want ErrInvalidPointer", pointer, err)
+		}
+	}
+
+	array := jsonds.NewArray(jsonds.ObjectItem(jsonds.Integer("x", 1)))
+	if v, err := array.Get("/0/x"); err != nil || mustBuild(t, jsonds.NewArray(v).Build) != "[1]" {
+		t.Errorf("Get on a root array returned %v", err)
+	}
+}
+
+func TestJsondsPointerEdit(t *testing.T) {
+	base := pointerBase()
+
+	tests := []struct {
+		name     string
+		edit     func() (jsonds.RootObject, error)
+		expected string
+	}{
+		{"set member", func() (jsonds.RootObject, error) {
+			return base.Set("/profile/bio", jsonds.StringItem("bye"))
+		}, `{"id":1,"profile":{"bio":"bye","tags":["a","b"]},"a/b~c":"escaped","deprecated":true}`},
+		{"set element", func() (jsonds.RootObject, error) {
+			return base.Set("/profile/tags/0", jsonds.ObjectItem(jsonds.Null("z")))
+		}, `{"id":1,"profile":{"bio":"hello","tags":[{"z":null},"b"]},"a/b~c":"escaped","deprecated":true}`},
+		{"set root", func() (jsonds.RootObject, error) {
+			return base.Set("", jsonds.ObjectItem(jsonds.Integer("n", 2)))
+		}, `{"n":2}`},
+		{"insert member", func() (jsonds.RootObject, error) {
+			return base.Insert("/profile/links", jsonds.ArrayItem())
+		}, `{"id":1,"profile":{"bio":"hello","tags":["a","b"],"links":[]},"a/b~c":"escaped","deprecated":true}`},
+		{"insert existing member", func() (jsonds.RootObject, error) {
+			return base.Insert("/id", jsonds.IntegerItem(2))
+		}, `{"id":2,"profile":{"bio":"hello","tags":["a","b"]},"a/b~c":"escaped","deprecated":true}`},
+		{"insert element", func() (jsonds.RootObject, error) {
+			return base.Insert("/profile/tags/1", jsonds.StringItem("x"))
+		}, `{"id":1,"profile":{"bio":"hello","tags":["a","x","b"]},"a/b~c":"escaped","deprecated":true}`},
+		{"insert at the end", func() (jsonds.RootObject, error) {
+			return base.Insert("/profile/tags/2", jsonds.StringItem("x"))
+		}, `{"id":1,"profile":{"bio":"hello","tags":["a","b","x"]},"a/b~c":"escaped","deprecated":true}`},
+		{"append", func() (jsonds.RootObject, error) {
+			return base.Insert("/profile/tags/-", jsonds.StringItem("x"))
+		}, `{"id":1,"profile":{"bio":"hello","tags":["a","b","x"]},"a/b~c":"escaped","deprecated":true}`},
+		{"delete member", func() (jsonds.RootObject, error) {
+			return base.Delete("/deprecated")
+		}, `{"id":1,"profile":{"bio":"hello","tags":["a","b"]},"a/b~c":"escaped"}`},
+		{"delete element", func() (jsonds.RootObject, error) {
+			return base.Delete("/profile/tags/0")
+		}, `{"id":1,"profile":{"bio":"hello","tags":["b"]},"a/b~c":"escaped","deprecated":true}`},
+	}
+	for _, tt := range tests {
+		edited, err := tt.edit()
+		if err != nil {
+			t.Errorf("%s failed: %v", tt.name, err)
+			continue
+		}
+		if got := mustBuild(t, edited.Build); got != tt.expected {
+			t.Errorf("%s wrote %s, want %s", tt.name, got, tt.expected)
+		}
+	}
+
+	// Edits copy the path, so the base and its shared subtrees are left as they were.
+	if got := mustBuild(t, base.Build); got != pointerBaseJSON {
+		t.Errorf("the base was modified: %s", got)
+	}
+
+	failures := map[string]func() (jsonds.RootObject, error){
+		"set missing":          func() (jsonds.RootObject, error) { return base.Set("/missing", jsonds.NullItem()) },
+		"set past the end":     func() (jsonds.RootObject, error) { return base.Set("/profile/tags/2", jsonds.NullItem()) },
+		"insert past the end":  func() (jsonds.RootObject, error) { return base.Insert("/profile/tags/3", jsonds.NullItem()) },
+		"insert into a scalar": func() (jsonds.RootObject, error) { return base.Insert("/id/x", jsonds.NullItem()) },
+		"insert under missing": func() (jsonds.RootObject, error) { return base.Insert("/a/b", jsonds.NullItem()) },
+		"delete missing":       func() (jsonds.RootObject, error) { return base.Delete("/profile/tags/-") },
+		"delete the root":      func() (jsonds.RootObject, error) { return base.Delete("") },
+		"set the root":         func() (jsonds.RootObject, error) { return base.Set("", jsonds.ArrayItem()) },
+		"invalid pointer":      func() (jsonds.RootObject, error) { return base.Delete("id") },
+	}
+	for name, edit := range failures {
+		edited, err := edit()
+		if err == nil {
+			t.Errorf("%s succeeded", name)
+			continue
+		}
+		if got := mustBuild(t, edited.Build); got != pointerBaseJSON {
+			t.Errorf("%s returned %s, want the base", name, got)
+		}
+	}
+}
+
+func TestJsondsPointerEditArray(t *testing.T) {
+	shared := jsonds.ObjectItem(jsonds.Array("tags", jsonds.StringItem("a")))
+	base := jsonds.NewArray(shared, jsonds.IntegerItem(1))
+
+	edited, err := base.Insert("/0/tags/0", jsonds.StringItem("b"))
+	if err == nil {
+		edited, err = edited.Delete("/1")
+	}
+	if err == nil {
+		edited, err = edited.Set("/-", jsonds.NullItem())
+	}
+	if !errors.Is(err, jsonds.ErrNotFound) {
+		t.Fatalf("Set at /- returned %v, want ErrNotFound", err)
+	}
+	if got := mustBuild(t, edited.Build); got != `[{"tags":["b","a"]}]` {
+		t.Errorf("edited array is %s", got)
+	}
+
+	// The base and the subtree it shares are unchanged.
+	if got := mustBuild(t, base.Build); got != `[{"tags":["a"]},1]` {
+		t.Errorf("the base was modified: %s", got)
+	}
+	if got := mustBuild(t, jsonds.NewArray(shared).Build); got != `[{"tags":["a"]}]` {
+		t.Errorf("the shared subtree was modified: %s", got)
+	}
+}
+
+func TestJsondsPointerEscapedNames(t *testing.T) {
+	parsed, err := jsonds.Parse([]byte(`{"a\u003cb":1,"q\"":{"\u00e9":2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := jsonds.FromAny(map[string]any{"a<b": 1, `q"`: map[string]any{"é": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted := jsonds.NewArray(value)
+
+	// Pointers reference members by their key, whatever the escaped form of their name.
+	for pointer, expected := range map[string]string{"/a<b": "1", `/q"/é`: "2"} {
+		if v, err := parsed.Get(pointer); err != nil || mustBuild(t, jsonds.NewArray(v).Build) != "["+expected+"]" {
+			t.Errorf("parsed Get(%q) returned %v", pointer, err)
+		}
+		if v, err := converted.Get("/0" + pointer); err != nil || mustBuild(t, jsonds.NewArray(v).Build) != "["+expected+"]" {
+			t.Errorf("converted Get(%q) returned %v", pointer, err)
+		}
+	}
+
+	edited, err := parsed.Set("/a<b", jsonds.IntegerItem(3))
+	if err == nil {
+		edited, err = edited.Insert("/x>y", jsonds.BooleanItem(true))
+	}
+	if err == nil {
+		edited, err = edited.Delete(`/q"`)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, edited.Build); got != `{"a\u003cb":3,"x\u003ey":true}` {
+		t.Errorf("edited tree is %s", got)
+	}
+
+	inserted, err := converted.Insert(`/0/q"/é`, jsonds.NullItem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, inserted.Build); got != `[{"a\u003cb":1,"q\"":{"é":null}}]` {
+		t.Errorf("edited tree is %s", got)
+	}
+}
+
+func TestJsondsPointerCustom(t *testing.T) {
+	root := jsonds.New(
+		jsonds.String("a", "x"),
+		jsonds.Each("list", []int{1, 2}, func(item int) jsonds.Value { return jsonds.IntegerItem(int64(item)) }),
+	)
+	item := jsonds.CustomItem(func(w *jsoni.ArrayWriter) { w.StringValue("custom") })
+
+	// A Custom field writes its own member, so it can only be replaced or deleted in place.
+	if _, err := root.Get("/list"); !errors.Is(err, jsonds.ErrNotFound) {
+		t.Errorf("Get of a custom field returned %v, want ErrNotFound", err)
+	}
+	if _, err := root.Get("/list/0"); !errors.Is(err, jsonds.ErrNotFound) {
+		t.Errorf("Get into a custom field returned %v, want ErrNotFound", err)
+	}
+	replaced, err := root.Set("/list", jsonds.NullItem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, replaced.Build); got != `{"a":"x","list":null}` {
+		t.Errorf("replaced tree is %s", got)
+	}
+
+	// A CustomItem value writes an array element, so it can't become an object member.
+	if edited, err := root.Set("/a", item); !errors.Is(err, jsonds.ErrCustomMember) {
+		t.Errorf("Set of a custom value returned %v, want ErrCustomMember", err)
+	} else if got := mustBuild(t, edited.Build); got != `{"a":"x","list":[1,2]}` {
+		t.Errorf("tree after a failed Set is %s", got)
+	}
+	if _, err := root.Insert("/other", item); !errors.Is(err, jsonds.ErrCustomMember) {
+		t.Errorf("Insert of a custom value returned %v, want ErrCustomMember", err)
+	}
+
+	array := jsonds.NewArray(jsonds.IntegerItem(1), item)
+	v, err := array.Get("/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited, err := array.Insert("/0", v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustBuild(t, edited.Build); got != `["custom",1,"custom"]` {
+		t.Errorf("edited array is %s", got)
+	}
+}
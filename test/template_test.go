@@ -0,0 +1,259 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsoni"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// userTemplate is the shape of a user, with every leaf value left to be bound.
+var userTemplate = mustCompile(jsondf.New(
+	jsondf.Slot[int64]("id"),
+	jsondf.Slot[string]("name"),
+	jsondf.Slot[string]("email"),
+	jsondf.Slot[bool]("is_active"),
+	jsondf.Slot[int64]("age"),
+	jsondf.Slot[float64]("balance"),
+	jsondf.ValueSlot("tags"),
+	jsondf.Object("profile",
+		jsondf.Slot[string]("bio"),
+		jsondf.Slot[string]("avatar_url"),
+	),
+	jsondf.ValueSlot("addresses"),
+))
+
+// addressTemplate is the shape of an address, executed for each address of a user.
+var addressTemplate = mustCompile(jsondf.New(
+	jsondf.Slot[string]("street"),
+	jsondf.Slot[string]("city"),
+	jsondf.Slot[string]("zip"),
+	jsondf.Slot[string]("country"),
+))
+
+func mustCompile(root jsondf.RootObject) *jsondf.Template {
+	t, err := jsondf.Compile(root)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func writeUsersJsondfTemplate(users []User) []byte {
+	w := jwriter.Writer{}
+	w.RawByte('[')
+	b := userTemplate.Bind()
+	ab := addressTemplate.Bind()
+	addressItem := jsondf.AnyItem(ab)
+	for i, u := range users {
+		if i > 0 {
+			w.RawByte(',')
+		}
+
+		var tags jsondf.Value
+		if u.Tags != nil {
+			tags = jsondf.EachItem(u.Tags, jsondf.StringItem)
+		} else {
+			tags = jsondf.NullItem()
+		}
+		var addresses jsondf.Value
+		if u.Addresses != nil {
+			addresses = jsondf.EachItem(u.Addresses, func(addr Address) jsondf.Value {
+				ab.Reset()
+				jsondf.Bind(ab, "street", addr.Street)
+				jsondf.Bind(ab, "city", addr.City)
+				jsondf.Bind(ab, "zip", addr.Zip)
+				jsondf.Bind(ab, "country", addr.Country)
+				return addressItem
+			})
+		} else {
+			addresses = jsondf.NullItem()
+		}
+
+		b.Reset()
+		jsondf.Bind(b, "id", u.ID)
+		jsondf.Bind(b, "name", u.Name)
+		jsondf.Bind(b, "email", u.Email)
+		jsondf.Bind(b, "is_active", u.IsActive)
+		jsondf.Bind(b, "age", int64(u.Age))
+		jsondf.Bind(b, "balance", u.Balance)
+		jsondf.BindValue(b, "tags", tags)
+		jsondf.Bind(b, "bio", u.Profile.Bio)
+		jsondf.Bind(b, "avatar_url", u.Profile.AvatarURL)
+		jsondf.BindValue(b, "addresses", addresses)
+		if err := b.ExecuteInto(&w); err != nil {
+			return nil
+		}
+	}
+	w.RawByte(']')
+	result, _ := w.BuildBytes()
+	return result
+}
+
+func TestJsondfTemplate(t *testing.T) {
+	users := generateUsers(100)
+	expected := writeUsersJsondf(users)
+
+	if result := writeUsersJsondfTemplate(users); string(result) != string(expected) {
+		t.Errorf("Users json are different:\n%s\n%s", result, expected)
+	}
+
+	names := strings.Join(userTemplate.Slots(), ",")
+	if names != "id,name,email,is_active,age,balance,tags,bio,avatar_url,addresses" {
+		t.Errorf("Unexpected slots %s", names)
+	}
+}
+
+func TestJsondfTemplateStatic(t *testing.T) {
+	tmpl, err := jsondf.CompileArray(jsondf.NewArray(
+		jsondf.StringItem("<static>"),
+		jsondf.SlotItem[string]("s"),
+		jsondf.ObjectItem(jsondf.Slot[string]("s"), jsondf.Float("f", 1.5)),
+		jsondf.ValueSlotItem("v"),
+	))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	b := tmpl.Bind()
+	jsondf.Bind(b, "s", "a\x00\"b")
+	jsondf.BindValue(b, "v", jsondf.ArrayItem(jsondf.NullItem()))
+	result, err := b.Execute()
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := `["\u003cstatic\u003e","a\u0000\"b",{"s":"a\u0000\"b","f":1.5},[null]]`
+	if string(result) != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestJsondfTemplateNulBytes(t *testing.T) {
+	// Field names and raw values are written as they are, so NUL bytes in the static parts
+	// and in slot names must not be taken for slots.
+	tmpl, err := jsondf.Compile(jsondf.New(
+		jsondf.String("a\x00b", "x"),
+		jsondf.Custom("raw", func(w *jsoni.ObjectWriter) { w.RawFields([]byte("\"raw\":\"\x00s\x00\"")) }),
+		jsondf.Slot[string]("s\x00"),
+		jsondf.Frozen(jsondf.Object("frozen", jsondf.Slot[int64]("i"))),
+	))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if names := strings.Join(tmpl.Slots(), ","); names != "s\x00,i" {
+		t.Errorf("Unexpected slots %q", names)
+	}
+
+	b := tmpl.Bind()
+	jsondf.Bind(b, "s\x00", "v")
+	jsondf.Bind(b, "i", int64(1))
+	result, err := b.Execute()
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if expected := "{\"a\x00b\":\"x\",\"raw\":\"\x00s\x00\",\"s\x00\":\"v\",\"frozen\":{\"i\":1}}"; string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestJsondfTemplateErrors(t *testing.T) {
+	tmpl := mustCompile(jsondf.New(
+		jsondf.Slot[int64]("id"),
+		jsondf.Slot[float64]("score"),
+		jsondf.ValueSlot("list"),
+	))
+
+	tests := []struct {
+		name string
+		bind func(b *jsondf.Binding)
+		err  error
+	}{
+		{"unbound", func(b *jsondf.Binding) {
+			jsondf.Bind(b, "id", int64(1))
+		}, jsondf.ErrUnboundSlot},
+		{"unknown", func(b *jsondf.Binding) {
+			jsondf.Bind(b, "other", int64(1))
+		}, jsondf.ErrUnknownSlot},
+		{"type", func(b *jsondf.Binding) {
+			jsondf.Bind(b, "id", "1")
+		}, jsondf.ErrSlotType},
+		{"nil value", func(b *jsondf.Binding) {
+			jsondf.Bind(b, "id", int64(1))
+			jsondf.Bind(b, "score", 1.5)
+			jsondf.BindValue(b, "list", nil)
+		}, jsondf.ErrUnboundSlot},
+		{"nan", func(b *jsondf.Binding) {
+			jsondf.Bind(b, "id", int64(1))
+			jsondf.Bind(b, "score", math.NaN())
+		}, jsoni.ErrUnsupportedFloat},
+	}
+
+	for _, tt := range tests {
+		b := tmpl.Bind()
+		tt.bind(b)
+		if _, err := b.Execute(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+
+	if _, err := jsondf.New(jsondf.Slot[int64]("id")).Build(); !errors.Is(err, jsondf.ErrUnboundSlot) {
+		t.Errorf("Expected a direct build to fail with %v, got %v", jsondf.ErrUnboundSlot, err)
+	}
+
+	_, err := jsondf.Compile(jsondf.New(jsondf.Slot[int64]("id"), jsondf.Slot[string]("id")))
+	if err == nil {
+		t.Error("Expected slots of different types with the same name to fail")
+	}
+}
+
+func TestJsondfTemplateAllocations(t *testing.T) {
+	tmpl := mustCompile(jsondf.New(
+		jsondf.Slot[int64]("id"),
+		jsondf.Slot[string]("name"),
+		jsondf.Object("flags", jsondf.Slot[bool]("active"), jsondf.Boolean("static", true)),
+		jsondf.Slot[float64]("score"),
+	))
+	b := tmpl.Bind()
+	name := "John"
+
+	w := jwriter.Writer{}
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		w.Buffer.Buf = buf
+		b.Reset()
+		jsondf.Bind(b, "id", int64(12345))
+		jsondf.Bind(b, "name", name)
+		jsondf.Bind(b, "active", true)
+		jsondf.Bind(b, "score", 1.5)
+		if err := b.ExecuteInto(&w); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+	if expected := `{"id":12345,"name":"John","flags":{"active":true,"static":true},"score":1.5}`; string(w.Buffer.Buf) != expected {
+		t.Errorf("Expected %s, got %s", expected, w.Buffer.Buf)
+	}
+}
+
+func TestJsondfTemplateConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			users := generateUsers(20)
+			if string(writeUsersJsondfTemplate(users)) != string(writeUsersJsondf(users)) {
+				t.Error("Users json are different")
+			}
+		}()
+	}
+	wg.Wait()
+}
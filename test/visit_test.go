@@ -0,0 +1,184 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/binadel/jsonw/jsond"
+	"github.com/binadel/jsonw/jsondf"
+	"github.com/binadel/jsonw/jsondi"
+	"github.com/binadel/jsonw/jsonds"
+	"github.com/binadel/jsonw/jsoni"
+)
+
+// visitRecorder records the calls of a walk, one line each, and skips the containers at skip.
+type visitRecorder struct {
+	lines []string
+	skip  string
+}
+
+func (r *visitRecorder) EnterObject(path string, n jsond.Node) bool {
+	r.lines = append(r.lines, fmt.Sprintf("{ %q %d", path, len(n.Children)))
+	return path != r.skip
+}
+
+func (r *visitRecorder) LeaveObject(path string, n jsond.Node) {
+	r.lines = append(r.lines, fmt.Sprintf("} %q", path))
+}
+
+func (r *visitRecorder) EnterArray(path string, n jsond.Node) bool {
+	r.lines = append(r.lines, fmt.Sprintf("[ %q %d", path, len(n.Children)))
+	return path != r.skip
+}
+
+func (r *visitRecorder) LeaveArray(path string, n jsond.Node) {
+	r.lines = append(r.lines, fmt.Sprintf("] %q", path))
+}
+
+func (r *visitRecorder) Scalar(path string, n jsond.Node) {
+	if n.Kind == jsond.Custom {
+		r.lines = append(r.lines, fmt.Sprintf("%s %q %q", n.Kind, path, n.Name))
+		return
+	}
+	r.lines = append(r.lines, fmt.Sprintf("%s %q %v", n.Kind, path, n.Value))
+}
+
+// visitExpected is the walk of the documents of TestWalk, whose /skipped array is skipped.
+const visitExpected = `{ "" 9
+Integer "/id" 42
+{ "/profile" 3
+String "/profile/a~1b~0c" slash and tilde
+Float "/profile/score" 1.5
+[ "/profile/tags" 3
+String "/profile/tags/0" go
+Null "/profile/tags/1" <nil>
+{ "/profile/tags/2" 1
+Boolean "/profile/tags/2/ok" true
+} "/profile/tags/2"
+] "/profile/tags"
+} "/profile"
+[ "/skipped" 2
+Number "/number" 1e3
+ValueOf "/list" [x y]
+Custom "/custom" "custom"
+Custom "/each" "each"
+Custom "/frozen" "frozen"
+[ "/items" 3
+Custom "/items/0" ""
+Custom "/items/1" ""
+Custom "/items/2" ""
+] "/items"
+} ""`
+
+func TestWalk(t *testing.T) {
+	var calls int
+	custom := func(w *jsoni.ObjectWriter) { calls++ }
+	customItem := func(w *jsoni.ArrayWriter) { calls++ }
+
+	walks := map[string]func(v jsond.Visitor){
+		"jsondf": jsondf.New(
+			jsondf.Integer("id", 42),
+			jsondf.Object("profile",
+				jsondf.String("a/b~c", "slash and tilde"),
+				jsondf.Float("score", 1.5),
+				jsondf.Array("tags", jsondf.StringItem("go"), jsondf.NullItem(), jsondf.ObjectItem(jsondf.Boolean("ok", true))),
+			),
+			jsondf.Array("skipped", jsondf.IntegerItem(1), jsondf.IntegerItem(2)),
+			jsondf.Number("number", "1e3"),
+			jsondf.ValueOf("list", tagList{"x", "y"}),
+			jsondf.Custom("custom", custom),
+			jsondf.Each("each", []int{1}, func(int) jsondf.Value { calls++; return jsondf.NullItem() }),
+			jsondf.Frozen(jsondf.Object("frozen", jsondf.Custom("inner", custom))),
+			jsondf.Array("items",
+				jsondf.CustomItem(customItem),
+				jsondf.EachItem([]int{1}, func(int) jsondf.Value { calls++; return jsondf.NullItem() }),
+				jsondf.FrozenItem(jsondf.CustomItem(customItem)),
+			),
+		).Walk,
+		"jsondi": jsondi.New(
+			jsondi.Integer("id", 42),
+			jsondi.Object("profile",
+				jsondi.String("a/b~c", "slash and tilde"),
+				jsondi.Float("score", 1.5),
+				jsondi.Array("tags", jsondi.StringItem("go"), jsondi.NullItem(), jsondi.ObjectItem(jsondi.Boolean("ok", true))),
+			),
+			jsondi.Array("skipped", jsondi.IntegerItem(1), jsondi.IntegerItem(2)),
+			jsondi.Number("number", "1e3"),
+			jsondi.ValueOf("list", tagList{"x", "y"}),
+			jsondi.Custom("custom", custom),
+			jsondi.Each("each", []int{1}, func(int) jsondi.Value { calls++; return jsondi.NullItem() }),
+			jsondi.Frozen(jsondi.Object("frozen", jsondi.Custom("inner", custom))),
+			jsondi.Array("items",
+				jsondi.CustomItem(customItem),
+				jsondi.EachItem([]int{1}, func(int) jsondi.Value { calls++; return jsondi.NullItem() }),
+				jsondi.FrozenItem(jsondi.CustomItem(customItem)),
+			),
+		).Walk,
+		"jsonds": jsonds.New(
+			jsonds.Integer("id", 42),
+			jsonds.Object("profile",
+				jsonds.String("a/b~c", "slash and tilde"),
+				jsonds.Float("score", 1.5),
+				jsonds.Array("tags", jsonds.StringItem("go"), jsonds.NullItem(), jsonds.ObjectItem(jsonds.Boolean("ok", true))),
+			),
+			jsonds.Array("skipped", jsonds.IntegerItem(1), jsonds.IntegerItem(2)),
+			jsonds.Number("number", "1e3"),
+			jsonds.ValueOf("list", tagList{"x", "y"}),
+			jsonds.Custom("custom", custom),
+			jsonds.Each("each", []int{1}, func(int) jsonds.Value { calls++; return jsonds.NullItem() }),
+			jsonds.Frozen(jsonds.Object("frozen", jsonds.Custom("inner", custom))),
+			jsonds.Array("items",
+				jsonds.CustomItem(customItem),
+				jsonds.EachItem([]int{1}, func(int) jsonds.Value { calls++; return jsonds.NullItem() }),
+				jsonds.FrozenItem(jsonds.CustomItem(customItem)),
+			),
+		).Walk,
+	}
+
+	for name, walk := range walks {
+		r := &visitRecorder{skip: "/skipped"}
+		walk(r)
+		if got := strings.Join(r.lines, "\n"); got != visitExpected {
+			t.Errorf("%s walked\n%s\nwant\n%s", name, got, visitExpected)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("walking called user functions %d times, want none", calls)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	// A description can be rebuilt with the API of any package.
+	root := jsondi.New(
+		jsondi.String("name", "John"),
+		jsondi.Array("tags", jsondi.StringItem("a"), jsondi.IntegerItem(1)),
+	).Describe()
+	expected := `{"name":"John","tags":["a",1]}`
+
+	for _, build := range []func(jsond.Node) ([]byte, error){jsondf.API.Build, jsondi.API.Build, jsonds.API.Build} {
+		got, err := build(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Errorf("rebuilt %s, want %s", got, expected)
+		}
+	}
+
+	// Functions adapted to a jsondf Field are described as unnamed Custom nodes, without being called.
+	called := false
+	raw := jsondf.Describe(jsondf.FieldFunc(func(w *jsoni.ObjectWriter) {
+		called = true
+		w.StringField("raw", "x")
+	}))
+	if raw.Kind != jsond.Custom || raw.Name != "" || raw.Value == nil {
+		t.Errorf("described a raw function as %+v", raw)
+	}
+	if called {
+		t.Error("describing a raw function called it")
+	}
+	if item := jsondf.DescribeItem(jsondf.ValueFunc(func(w *jsoni.ArrayWriter) { called = true })); item.Kind != jsond.Custom || called {
+		t.Errorf("described a raw function as %+v, called: %v", item, called)
+	}
+}